@@ -0,0 +1,238 @@
+// Package a11y finds common accessibility problems in g trees: images
+// without alternative text, unlabeled form controls, skipped heading levels,
+// buttons without a name, duplicate ids and documents without a language.
+//
+// It is meant for unit tests:
+//
+//	func TestHomePage(t *testing.T) {
+//		a11y.Check(t, homePage())
+//	}
+package a11y
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+// Rule identifies the check that found a Problem.
+type Rule string
+
+const (
+	RuleImgAlt       Rule = "img-alt"       // Img without alt
+	RuleLabel        Rule = "label"         // form control without a label
+	RuleHeadingOrder Rule = "heading-order" // heading level skipped
+	RuleButtonName   Rule = "button-name"   // button without an accessible name
+	RuleDuplicateID  Rule = "duplicate-id"  // id used more than once
+	RuleHtmlLang     Rule = "html-lang"     // Html without lang
+)
+
+// Problem is an accessibility problem found in a tree.
+//
+// Path is the list of child indexes of the offending element, counted like
+// the Path of a g.Patch.
+type Problem struct {
+	Path    []int
+	Rule    Rule
+	Message string
+}
+
+func (me Problem) String() string {
+	return fmt.Sprintf("%s at %v: %s", me.Rule, me.Path, me.Message)
+}
+
+// Check reports every problem found in node as a test error.
+func Check(t testing.TB, node g.Node) {
+	t.Helper()
+	for _, p := range Lint(node) {
+		t.Errorf("a11y: %s\n%s", p, subtree(node, p.Path))
+	}
+}
+
+// Lint returns the problems found in node, in tree order.
+func Lint(node g.Node) []Problem {
+	l := &linter{labeled: map[any]bool{}}
+
+	// labels may come after the controls they label
+	g.Walk(node, func(node g.Node) bool {
+		if e, ok := node.(*g.Element); ok && strings.EqualFold(e.Tag, "label") {
+			if id, ok := idAttr(e, "for"); ok {
+				l.labeled[id] = true
+			}
+		}
+		return true
+	})
+
+	for i, root := range g.Flatten([]g.Node{node}) {
+		l.node([]int{i}, root, false)
+	}
+	for _, issue := range g.DuplicateIDs(node) {
+		l.add(issue.Path, RuleDuplicateID, "%s", issue.Message)
+	}
+	slices.SortStableFunc(l.problems, func(a, b Problem) int {
+		return slices.Compare(a.Path, b.Path)
+	})
+	return l.problems
+}
+
+type linter struct {
+	problems     []Problem
+	labeled      map[any]bool // ids referenced by the for attribute of a label
+	headingLevel int          // level of the last heading, 0 before the first one
+}
+
+func (me *linter) add(path []int, rule Rule, format string, args ...any) {
+	me.problems = append(me.problems, Problem{Path: path, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+// node checks the element at path and its descendants. inLabel tells
+// whether an ancestor is a label.
+func (me *linter) node(path []int, node g.Node, inLabel bool) {
+	e, ok := node.(*g.Element)
+	if !ok {
+		return
+	}
+	tag := strings.ToLower(e.Tag)
+
+	switch tag {
+	case "html":
+		if lang, _ := attr(e, "lang"); strings.TrimSpace(lang) == "" {
+			me.add(path, RuleHtmlLang, "<html> has no lang attribute")
+		}
+	case "img":
+		if _, ok := attr(e, "alt"); !ok {
+			me.add(path, RuleImgAlt, `<img> has no alt attribute; use alt="" for decorative images`)
+		}
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(tag[1] - '0')
+		if me.headingLevel > 0 && level > me.headingLevel+1 {
+			me.add(path, RuleHeadingOrder, "<%s> follows <h%d>, skipping a level", tag, me.headingLevel)
+		}
+		me.headingLevel = level
+	case "input", "select", "textarea":
+		if tag == "input" {
+			switch t, _ := attr(e, "type"); strings.ToLower(t) {
+			case "hidden", "submit", "reset", "image":
+				// not shown, or named by their default value or alt
+			case "button":
+				if value, _ := attr(e, "value"); strings.TrimSpace(value) == "" && !hasAriaName(e) {
+					me.add(path, RuleButtonName, `<input type="button"> has no value or aria-label`)
+				}
+			default:
+				me.label(path, tag, e, inLabel)
+			}
+		} else {
+			me.label(path, tag, e, inLabel)
+		}
+	case "button":
+		if strings.TrimSpace(textContent(e)) == "" && !hasAriaName(e) {
+			me.add(path, RuleButtonName, "<button> has no text, aria-label or title")
+		}
+	case "label":
+		inLabel = true
+	}
+
+	for i, child := range g.Flatten(e.Children) {
+		me.node(append(slices.Clip(path), i), child, inLabel)
+	}
+}
+
+// label checks that the form control e has a label.
+func (me *linter) label(path []int, tag string, e *g.Element, inLabel bool) {
+	if inLabel || hasAriaName(e) {
+		return
+	}
+	if id, ok := idAttr(e, "id"); ok && me.labeled[id] {
+		return
+	}
+	me.add(path, RuleLabel, "<%s> has no associated <label>", tag)
+}
+
+// hasAriaName reports whether e is named by an attribute.
+func hasAriaName(e *g.Element) bool {
+	for _, key := range []string{"aria-label", "aria-labelledby", "title"} {
+		if v, _ := attr(e, key); strings.TrimSpace(v) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// textContent returns the text of node as read by assistive technologies,
+// including the alt text of images.
+func textContent(node g.Node) string {
+	switch n := node.(type) {
+	case g.Text:
+		return string(n)
+	case *g.Element:
+		if strings.EqualFold(n.Tag, "img") {
+			alt, _ := attr(n, "alt")
+			return alt
+		}
+		if label, _ := attr(n, "aria-label"); label != "" {
+			return label
+		}
+		var sb strings.Builder
+		for _, child := range n.Children {
+			sb.WriteString(textContent(child))
+		}
+		return sb.String()
+	case nil:
+		return ""
+	default:
+		s, _ := n.Render()
+		return s
+	}
+}
+
+// attr returns the value of the attribute key of e, and whether it is
+// rendered.
+func attr(e *g.Element, key string) (string, bool) {
+	switch v := e.Attrs[key].(type) {
+	case string:
+		return v, true
+	case bool:
+		return "", v
+	case g.AttrValuer:
+		s, err := v.AttrValue()
+		return s, err == nil
+	default:
+		return "", false
+	}
+}
+
+// idAttr returns the id held by the attribute key of e: a quoted string
+// for literal ids, or the *g.ID generated at render time.
+func idAttr(e *g.Element, key string) (any, bool) {
+	if id, ok := e.Attrs[key].(*g.ID); ok {
+		return id, true
+	}
+	id, ok := attr(e, key)
+	return fmt.Sprintf("%q", strings.TrimSpace(id)), ok
+}
+
+// subtree renders the element at path, for error messages.
+func subtree(node g.Node, path []int) string {
+	nodes := g.Flatten([]g.Node{node})
+	for i, index := range path {
+		if index >= len(nodes) {
+			return ""
+		}
+		node = nodes[index]
+		if i < len(path)-1 {
+			e, ok := node.(*g.Element)
+			if !ok {
+				return ""
+			}
+			nodes = g.Flatten(e.Children)
+		}
+	}
+	s, err := node.Render()
+	if err != nil {
+		return ""
+	}
+	return "\t" + s
+}
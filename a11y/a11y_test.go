@@ -0,0 +1,139 @@
+package a11y
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     g.Node
+		expected []string
+	}{
+		{
+			name: "Accessible page",
+			node: g.Html(g.KV{"lang": "en"}, g.Body(
+				g.H1(g.Text("Title")),
+				g.Img(g.KV{"src": "/logo.png", "alt": ""}),
+				g.H2(g.Text("Section")),
+				g.Form(
+					g.Label(g.KV{"for": "email"}, g.Text("Email")),
+					g.Input(g.KV{"id": "email", "type": "email"}),
+					g.Label(g.Text("Password"), g.Input(g.KV{"type": "password"})),
+					g.Textarea(g.KV{"aria-label": "Comment"}),
+					g.Input(g.KV{"type": "hidden", "name": "csrf"}),
+					g.Button(g.Img(g.KV{"src": "/send.png", "alt": "Send"})),
+				),
+				g.H2(g.Text("Other")),
+			)),
+			expected: nil,
+		},
+		{
+			name:     "Html without lang",
+			node:     g.Html(g.Body()),
+			expected: []string{"html-lang at [0]: <html> has no lang attribute"},
+		},
+		{
+			name:     "Img without alt",
+			node:     g.Div(g.Img(g.KV{"src": "/a.png"})),
+			expected: []string{`img-alt at [0 0]: <img> has no alt attribute; use alt="" for decorative images`},
+		},
+		{
+			name: "Form controls without a label",
+			node: g.Form(
+				g.Input(g.KV{"name": "q"}),
+				g.Select(g.Option(g.Text("a"))),
+				g.Label(g.KV{"for": "other"}, g.Text("Other")),
+				g.Textarea(g.KV{"id": "comment"}),
+			),
+			expected: []string{
+				"label at [0 0]: <input> has no associated <label>",
+				"label at [0 1]: <select> has no associated <label>",
+				"label at [0 3]: <textarea> has no associated <label>",
+			},
+		},
+		{
+			name: "Label after the control",
+			node: g.Empty(g.Input(g.KV{"id": "name"}), g.Label(g.KV{"for": "name"}, g.Text("Name"))),
+		},
+		{
+			name: "Label for a generated id",
+			node: func() g.Node {
+				id := g.UniqueID("name")
+				return g.Empty(g.Label(g.KV{"for": id}, g.Text("Name")), g.Input(g.KV{"id": id}), g.Input(g.KV{"id": g.UniqueID("name")}))
+			}(),
+			expected: []string{"label at [2]: <input> has no associated <label>"},
+		},
+		{
+			name: "Skipped heading levels",
+			node: g.Div(g.H1(), g.Section(g.H3()), g.H2(), g.H4()),
+			expected: []string{
+				"heading-order at [0 1 0]: <h3> follows <h1>, skipping a level",
+				"heading-order at [0 3]: <h4> follows <h2>, skipping a level",
+			},
+		},
+		{
+			name: "Buttons without a name",
+			node: g.Div(
+				g.Button(g.Span(g.Text(" "))),
+				g.Button(g.KV{"aria-label": "Close"}, g.Text("×")),
+				g.Button(g.Img(g.KV{"src": "/x.png"})),
+				g.Input(g.KV{"type": "button"}),
+			),
+			expected: []string{
+				"button-name at [0 0]: <button> has no text, aria-label or title",
+				"button-name at [0 2]: <button> has no text, aria-label or title",
+				"img-alt at [0 2 0]: <img> has no alt attribute; use alt=\"\" for decorative images",
+				`button-name at [0 3]: <input type="button"> has no value or aria-label`,
+			},
+		},
+		{
+			name: "Duplicate ids",
+			node: g.Div(g.P(g.KV{"id": "a"}), g.Empty(g.P(g.KV{"id": "b"}), g.P(g.KV{"id": "a"}))),
+			expected: []string{
+				`duplicate-id at [0 2]: id "a" is used more than once`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result []string
+			for _, p := range Lint(tt.node) {
+				result = append(result, p.String())
+			}
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("Lint() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// recorder is a testing.TB that records errors instead of failing.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (me *recorder) Helper() {}
+
+func (me *recorder) Errorf(format string, args ...any) {
+	me.errors = append(me.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheck(t *testing.T) {
+	r := &recorder{TB: t}
+	Check(r, g.Div(g.P(g.Img(g.KV{"src": "/a.png"}))))
+
+	if len(r.errors) != 1 {
+		t.Fatalf("Check() reported %d errors, want 1: %q", len(r.errors), r.errors)
+	}
+	if !strings.Contains(r.errors[0], "img-alt at [0 0 0]") || !strings.Contains(r.errors[0], `<img src="/a.png">`) {
+		t.Errorf("Check() error = %q, want the problem and the offending element", r.errors[0])
+	}
+}
@@ -0,0 +1,167 @@
+// Package csp applies a Content Security Policy to rendered pages: every
+// Script, Style and Link gets the nonce of the request, and the matching
+// Content-Security-Policy header is sent with the page.
+package csp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/assaidy/g"
+)
+
+type nonceKey struct{}
+
+// Middleware generates a nonce for every request and stores it in the
+// request context, where Nonce and Policy.Render find it.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithNonce(r.Context(), newNonce())))
+	})
+}
+
+// WithNonce returns a copy of ctx carrying nonce.
+func WithNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, nonceKey{}, nonce)
+}
+
+// Nonce returns the nonce stored in ctx, or "" if there is none.
+func Nonce(ctx context.Context) string {
+	nonce, _ := ctx.Value(nonceKey{}).(string)
+	return nonce
+}
+
+func newNonce() string {
+	b := make([]byte, 16)
+	rand.Read(b) // never returns an error
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// Policy is a Content Security Policy.
+//
+// Example:
+//
+//	policy := csp.Policy{
+//		Directives: map[string][]string{
+//			"default-src": {"'self'"},
+//			"img-src":     {"'self'", "data:"},
+//		},
+//	}
+//	mux.Handle("/", csp.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//		if err := policy.Render(w, r, page()); err != nil {
+//			...
+//		}
+//	})))
+type Policy struct {
+	// Directives maps directive names to their sources. The request nonce
+	// is added to script-src and style-src; when one of them is missing, it
+	// starts from the default-src sources.
+	Directives map[string][]string
+	// HashInline adds a 'sha256-...' source for the body of every inline
+	// script and style, on top of the nonce.
+	HashInline bool
+	// ReportOnly sends the policy in the
+	// Content-Security-Policy-Report-Only header instead.
+	ReportOnly bool
+}
+
+// Render sets the policy header and writes node to w, with the request
+// nonce on every script, style and link element, HeadItems included.
+//
+// The nonce is taken from the request context (see Middleware); a new one is
+// generated when there is none. It is added while rendering, so node isn't
+// modified and can be shared between requests.
+func (me Policy) Render(w http.ResponseWriter, r *http.Request, node g.Node) error {
+	nonce := Nonce(r.Context())
+	if nonce == "" {
+		nonce = newNonce()
+	}
+
+	var hashes map[string][]string
+	if me.HashInline {
+		var err error
+		if hashes, err = inlineHashes(node); err != nil {
+			return err
+		}
+	}
+
+	name := "Content-Security-Policy"
+	if me.ReportOnly {
+		name = "Content-Security-Policy-Report-Only"
+	}
+	w.Header().Set(name, me.header(nonce, hashes))
+	return g.Render(w, node, g.WithNonce(nonce))
+}
+
+// header builds the policy header, adding the nonce and the hashes of the
+// inline bodies (by directive) to the sources.
+func (me Policy) header(nonce string, hashes map[string][]string) string {
+	directives := make(map[string][]string, len(me.Directives)+2)
+	for name, sources := range me.Directives {
+		directives[strings.ToLower(name)] = slices.Clone(sources)
+	}
+	for _, name := range []string{"script-src", "style-src"} {
+		if _, ok := directives[name]; !ok {
+			directives[name] = slices.Clone(directives["default-src"])
+		}
+		directives[name] = append(directives[name], fmt.Sprintf("'nonce-%s'", nonce))
+		directives[name] = append(directives[name], hashes[name]...)
+	}
+
+	names := make([]string, 0, len(directives))
+	for name := range directives {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, strings.Join(append([]string{name}, directives[name]...), " "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// inlineHashes returns the hash sources of the bodies of the script and
+// style elements of node, by directive.
+func inlineHashes(node g.Node) (map[string][]string, error) {
+	hashes := map[string][]string{}
+	var err error
+	g.Walk(node, func(node g.Node) bool {
+		e, ok := node.(*g.Element)
+		if !ok || err != nil {
+			return false
+		}
+		directive := inlineDirective(e.Tag)
+		if directive == "" || len(e.Children) == 0 {
+			return true
+		}
+		var body string
+		if body, err = (&g.Element{Children: e.Children}).Render(); err != nil || body == "" {
+			return false
+		}
+		sum := sha256.Sum256([]byte(body))
+		source := fmt.Sprintf("'sha256-%s'", base64.StdEncoding.EncodeToString(sum[:]))
+		if !slices.Contains(hashes[directive], source) {
+			hashes[directive] = append(hashes[directive], source)
+		}
+		return false
+	})
+	return hashes, err
+}
+
+func inlineDirective(tag string) string {
+	switch strings.ToLower(tag) {
+	case "script":
+		return "script-src"
+	case "style":
+		return "style-src"
+	default:
+		return ""
+	}
+}
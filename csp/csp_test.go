@@ -0,0 +1,156 @@
+package csp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/assaidy/g"
+	"github.com/assaidy/g/css"
+)
+
+func TestPolicy_Render(t *testing.T) {
+	page := func() g.Node {
+		return g.Html(
+			g.Head(
+				g.Style(g.Text("body { margin: 0; }")),
+				g.Script(g.KV{"src": "/app.js"}),
+			),
+			g.Body(g.Script(g.Text("init();"))),
+		)
+	}
+
+	tests := []struct {
+		name       string
+		policy     Policy
+		header     string
+		wantHeader string
+		wantBody   string
+	}{
+		{
+			name:       "Nonce only",
+			policy:     Policy{Directives: map[string][]string{"default-src": {"'self'"}}},
+			header:     "Content-Security-Policy",
+			wantHeader: "default-src 'self'; script-src 'self' 'nonce-abc'; style-src 'self' 'nonce-abc'",
+			wantBody:   `<html><head><style nonce="abc">body { margin: 0; }</style><script nonce="abc" src="/app.js"></script></head><body><script nonce="abc">init();</script></body></html>`,
+		},
+		{
+			name: "Explicit directives and hashes",
+			policy: Policy{
+				Directives: map[string][]string{"script-src": {"https://cdn.example"}, "object-src": {"'none'"}},
+				HashInline: true,
+			},
+			header: "Content-Security-Policy",
+			wantHeader: "object-src 'none'; " +
+				"script-src https://cdn.example 'nonce-abc' 'sha256-GEnM5q1nYY/iACnyMTdov+tNp9OFcBnnDgNXUXaVNXc='; " +
+				"style-src 'nonce-abc' 'sha256-Pme0qVBbJGACcvHOa2d2xK4uveiPdlWdSipR9gLYAMQ='",
+		},
+		{
+			name:       "Report only",
+			policy:     Policy{ReportOnly: true},
+			header:     "Content-Security-Policy-Report-Only",
+			wantHeader: "script-src 'nonce-abc'; style-src 'nonce-abc'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(WithNonce(r.Context(), "abc"))
+			w := httptest.NewRecorder()
+
+			if err := tt.policy.Render(w, r, page()); err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if got := w.Header().Get(tt.header); got != tt.wantHeader {
+				t.Errorf("%s = %q, want %q", tt.header, got, tt.wantHeader)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("Render() = %q, want %q", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestPolicy_RenderSharedTree(t *testing.T) {
+	page := g.Html(
+		g.Head(g.Link(g.KV{"rel": "stylesheet", "href": "/app.css"})),
+		g.Body(g.HeadItem(g.Script(g.Text("x()")))),
+	)
+	policy := Policy{HashInline: true}
+
+	var wg sync.WaitGroup
+	for _, nonce := range []string{"a", "b", "c", "d"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(WithNonce(r.Context(), nonce))
+			w := httptest.NewRecorder()
+			if err := policy.Render(w, r, page); err != nil {
+				t.Errorf("Render() error: %v", err)
+				return
+			}
+			want := fmt.Sprintf(`<html><head><link href="/app.css" nonce="%s" rel="stylesheet"><script nonce="%[1]s">x()</script></head><body></body></html>`, nonce)
+			if w.Body.String() != want {
+				t.Errorf("Render() = %q, want %q", w.Body.String(), want)
+			}
+			if header := w.Header().Get("Content-Security-Policy"); !strings.Contains(header, "'sha256-") {
+				t.Errorf("Content-Security-Policy = %q, want the hash of the HeadItem script", header)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, ok := page.Children[0].(*g.Element).Children[0].(*g.Element).Attrs["nonce"]; ok {
+		t.Errorf("Render() modified the page")
+	}
+}
+
+func TestPolicy_RenderScopedCSS(t *testing.T) {
+	card := css.Scoped("& { padding: 0 }")
+	page := g.Html(g.Head(), g.Body(card.Apply(g.Div()), card.Apply(g.Div())))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(WithNonce(r.Context(), "abc"))
+	w := httptest.NewRecorder()
+	if err := (Policy{}).Render(w, r, page); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	want := `<html><head><style nonce="abc">.` + card.Class + ` { padding: 0 }</style></head>` +
+		`<body><div class="` + card.Class + `"></div><div class="` + card.Class + `"></div></body></html>`
+	if w.Body.String() != want {
+		t.Errorf("Render() = %q, want %q", w.Body.String(), want)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var nonces []string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonces = append(nonces, Nonce(r.Context()))
+		if err := (Policy{}).Render(w, r, g.Script(g.Text("x()"))); err != nil {
+			t.Errorf("Render() error: %v", err)
+		}
+	}))
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		nonce := nonces[len(nonces)-1]
+		if nonce == "" {
+			t.Fatal("Middleware() should store a nonce in the request context")
+		}
+		if !strings.Contains(w.Header().Get("Content-Security-Policy"), "'nonce-"+nonce+"'") {
+			t.Errorf("header %q doesn't contain the request nonce %q", w.Header().Get("Content-Security-Policy"), nonce)
+		}
+		if !strings.Contains(w.Body.String(), `nonce="`+nonce+`"`) {
+			t.Errorf("body %q doesn't contain the request nonce %q", w.Body.String(), nonce)
+		}
+	}
+	if nonces[0] == nonces[1] {
+		t.Error("Middleware() should generate a new nonce for every request")
+	}
+}
@@ -0,0 +1,687 @@
+package css
+
+// AlignContent declares the align-content property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/align-content
+func AlignContent(values ...Value) Prop {
+	return Declare("align-content", values...)
+}
+
+// AlignItems declares the align-items property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/align-items
+func AlignItems(values ...Value) Prop {
+	return Declare("align-items", values...)
+}
+
+// AlignSelf declares the align-self property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/align-self
+func AlignSelf(values ...Value) Prop {
+	return Declare("align-self", values...)
+}
+
+// Animation declares the animation property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/animation
+func Animation(values ...Value) Prop {
+	return Declare("animation", values...)
+}
+
+// AspectRatio declares the aspect-ratio property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/aspect-ratio
+func AspectRatio(values ...Value) Prop {
+	return Declare("aspect-ratio", values...)
+}
+
+// Background declares the background property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/background
+func Background(values ...Value) Prop {
+	return Declare("background", values...)
+}
+
+// BackgroundColor declares the background-color property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/background-color
+func BackgroundColor(values ...Value) Prop {
+	return Declare("background-color", values...)
+}
+
+// BackgroundImage declares the background-image property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/background-image
+func BackgroundImage(values ...Value) Prop {
+	return Declare("background-image", values...)
+}
+
+// BackgroundPosition declares the background-position property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/background-position
+func BackgroundPosition(values ...Value) Prop {
+	return Declare("background-position", values...)
+}
+
+// BackgroundRepeat declares the background-repeat property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/background-repeat
+func BackgroundRepeat(values ...Value) Prop {
+	return Declare("background-repeat", values...)
+}
+
+// BackgroundSize declares the background-size property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/background-size
+func BackgroundSize(values ...Value) Prop {
+	return Declare("background-size", values...)
+}
+
+// Border declares the border property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/border
+func Border(values ...Value) Prop {
+	return Declare("border", values...)
+}
+
+// BorderBottom declares the border-bottom property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/border-bottom
+func BorderBottom(values ...Value) Prop {
+	return Declare("border-bottom", values...)
+}
+
+// BorderCollapse declares the border-collapse property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/border-collapse
+func BorderCollapse(values ...Value) Prop {
+	return Declare("border-collapse", values...)
+}
+
+// BorderColor declares the border-color property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/border-color
+func BorderColor(values ...Value) Prop {
+	return Declare("border-color", values...)
+}
+
+// BorderLeft declares the border-left property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/border-left
+func BorderLeft(values ...Value) Prop {
+	return Declare("border-left", values...)
+}
+
+// BorderRadius declares the border-radius property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/border-radius
+func BorderRadius(values ...Value) Prop {
+	return Declare("border-radius", values...)
+}
+
+// BorderRight declares the border-right property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/border-right
+func BorderRight(values ...Value) Prop {
+	return Declare("border-right", values...)
+}
+
+// BorderStyle declares the border-style property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/border-style
+func BorderStyle(values ...Value) Prop {
+	return Declare("border-style", values...)
+}
+
+// BorderTop declares the border-top property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/border-top
+func BorderTop(values ...Value) Prop {
+	return Declare("border-top", values...)
+}
+
+// BorderWidth declares the border-width property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/border-width
+func BorderWidth(values ...Value) Prop {
+	return Declare("border-width", values...)
+}
+
+// Bottom declares the bottom property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/bottom
+func Bottom(values ...Value) Prop {
+	return Declare("bottom", values...)
+}
+
+// BoxShadow declares the box-shadow property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/box-shadow
+func BoxShadow(values ...Value) Prop {
+	return Declare("box-shadow", values...)
+}
+
+// BoxSizing declares the box-sizing property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/box-sizing
+func BoxSizing(values ...Value) Prop {
+	return Declare("box-sizing", values...)
+}
+
+// Color declares the color property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/color
+func Color(values ...Value) Prop {
+	return Declare("color", values...)
+}
+
+// ColumnGap declares the column-gap property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/column-gap
+func ColumnGap(values ...Value) Prop {
+	return Declare("column-gap", values...)
+}
+
+// Content declares the content property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/content
+func Content(values ...Value) Prop {
+	return Declare("content", values...)
+}
+
+// Cursor declares the cursor property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/cursor
+func Cursor(values ...Value) Prop {
+	return Declare("cursor", values...)
+}
+
+// Display declares the display property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/display
+func Display(values ...Value) Prop {
+	return Declare("display", values...)
+}
+
+// Flex declares the flex property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/flex
+func Flex(values ...Value) Prop {
+	return Declare("flex", values...)
+}
+
+// FlexBasis declares the flex-basis property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/flex-basis
+func FlexBasis(values ...Value) Prop {
+	return Declare("flex-basis", values...)
+}
+
+// FlexDirection declares the flex-direction property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/flex-direction
+func FlexDirection(values ...Value) Prop {
+	return Declare("flex-direction", values...)
+}
+
+// FlexGrow declares the flex-grow property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/flex-grow
+func FlexGrow(values ...Value) Prop {
+	return Declare("flex-grow", values...)
+}
+
+// FlexShrink declares the flex-shrink property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/flex-shrink
+func FlexShrink(values ...Value) Prop {
+	return Declare("flex-shrink", values...)
+}
+
+// FlexWrap declares the flex-wrap property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/flex-wrap
+func FlexWrap(values ...Value) Prop {
+	return Declare("flex-wrap", values...)
+}
+
+// Font declares the font property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/font
+func Font(values ...Value) Prop {
+	return Declare("font", values...)
+}
+
+// FontFamily declares the font-family property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/font-family
+func FontFamily(values ...Value) Prop {
+	return Declare("font-family", values...)
+}
+
+// FontSize declares the font-size property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/font-size
+func FontSize(values ...Value) Prop {
+	return Declare("font-size", values...)
+}
+
+// FontStyle declares the font-style property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/font-style
+func FontStyle(values ...Value) Prop {
+	return Declare("font-style", values...)
+}
+
+// FontWeight declares the font-weight property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/font-weight
+func FontWeight(values ...Value) Prop {
+	return Declare("font-weight", values...)
+}
+
+// Gap declares the gap property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/gap
+func Gap(values ...Value) Prop {
+	return Declare("gap", values...)
+}
+
+// GridArea declares the grid-area property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/grid-area
+func GridArea(values ...Value) Prop {
+	return Declare("grid-area", values...)
+}
+
+// GridColumn declares the grid-column property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/grid-column
+func GridColumn(values ...Value) Prop {
+	return Declare("grid-column", values...)
+}
+
+// GridRow declares the grid-row property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/grid-row
+func GridRow(values ...Value) Prop {
+	return Declare("grid-row", values...)
+}
+
+// GridTemplateAreas declares the grid-template-areas property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/grid-template-areas
+func GridTemplateAreas(values ...Value) Prop {
+	return Declare("grid-template-areas", values...)
+}
+
+// GridTemplateColumns declares the grid-template-columns property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/grid-template-columns
+func GridTemplateColumns(values ...Value) Prop {
+	return Declare("grid-template-columns", values...)
+}
+
+// GridTemplateRows declares the grid-template-rows property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/grid-template-rows
+func GridTemplateRows(values ...Value) Prop {
+	return Declare("grid-template-rows", values...)
+}
+
+// Height declares the height property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/height
+func Height(values ...Value) Prop {
+	return Declare("height", values...)
+}
+
+// Inset declares the inset property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/inset
+func Inset(values ...Value) Prop {
+	return Declare("inset", values...)
+}
+
+// JustifyContent declares the justify-content property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/justify-content
+func JustifyContent(values ...Value) Prop {
+	return Declare("justify-content", values...)
+}
+
+// JustifyItems declares the justify-items property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/justify-items
+func JustifyItems(values ...Value) Prop {
+	return Declare("justify-items", values...)
+}
+
+// JustifySelf declares the justify-self property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/justify-self
+func JustifySelf(values ...Value) Prop {
+	return Declare("justify-self", values...)
+}
+
+// Left declares the left property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/left
+func Left(values ...Value) Prop {
+	return Declare("left", values...)
+}
+
+// LetterSpacing declares the letter-spacing property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/letter-spacing
+func LetterSpacing(values ...Value) Prop {
+	return Declare("letter-spacing", values...)
+}
+
+// LineHeight declares the line-height property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/line-height
+func LineHeight(values ...Value) Prop {
+	return Declare("line-height", values...)
+}
+
+// ListStyle declares the list-style property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/list-style
+func ListStyle(values ...Value) Prop {
+	return Declare("list-style", values...)
+}
+
+// Margin declares the margin property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/margin
+func Margin(values ...Value) Prop {
+	return Declare("margin", values...)
+}
+
+// MarginBlock declares the margin-block property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/margin-block
+func MarginBlock(values ...Value) Prop {
+	return Declare("margin-block", values...)
+}
+
+// MarginBottom declares the margin-bottom property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/margin-bottom
+func MarginBottom(values ...Value) Prop {
+	return Declare("margin-bottom", values...)
+}
+
+// MarginInline declares the margin-inline property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/margin-inline
+func MarginInline(values ...Value) Prop {
+	return Declare("margin-inline", values...)
+}
+
+// MarginLeft declares the margin-left property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/margin-left
+func MarginLeft(values ...Value) Prop {
+	return Declare("margin-left", values...)
+}
+
+// MarginRight declares the margin-right property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/margin-right
+func MarginRight(values ...Value) Prop {
+	return Declare("margin-right", values...)
+}
+
+// MarginTop declares the margin-top property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/margin-top
+func MarginTop(values ...Value) Prop {
+	return Declare("margin-top", values...)
+}
+
+// MaxHeight declares the max-height property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/max-height
+func MaxHeight(values ...Value) Prop {
+	return Declare("max-height", values...)
+}
+
+// MaxWidth declares the max-width property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/max-width
+func MaxWidth(values ...Value) Prop {
+	return Declare("max-width", values...)
+}
+
+// MinHeight declares the min-height property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/min-height
+func MinHeight(values ...Value) Prop {
+	return Declare("min-height", values...)
+}
+
+// MinWidth declares the min-width property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/min-width
+func MinWidth(values ...Value) Prop {
+	return Declare("min-width", values...)
+}
+
+// ObjectFit declares the object-fit property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/object-fit
+func ObjectFit(values ...Value) Prop {
+	return Declare("object-fit", values...)
+}
+
+// Opacity declares the opacity property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/opacity
+func Opacity(values ...Value) Prop {
+	return Declare("opacity", values...)
+}
+
+// Order declares the order property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/order
+func Order(values ...Value) Prop {
+	return Declare("order", values...)
+}
+
+// Outline declares the outline property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/outline
+func Outline(values ...Value) Prop {
+	return Declare("outline", values...)
+}
+
+// Overflow declares the overflow property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/overflow
+func Overflow(values ...Value) Prop {
+	return Declare("overflow", values...)
+}
+
+// OverflowX declares the overflow-x property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/overflow-x
+func OverflowX(values ...Value) Prop {
+	return Declare("overflow-x", values...)
+}
+
+// OverflowY declares the overflow-y property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/overflow-y
+func OverflowY(values ...Value) Prop {
+	return Declare("overflow-y", values...)
+}
+
+// Padding declares the padding property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/padding
+func Padding(values ...Value) Prop {
+	return Declare("padding", values...)
+}
+
+// PaddingBlock declares the padding-block property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/padding-block
+func PaddingBlock(values ...Value) Prop {
+	return Declare("padding-block", values...)
+}
+
+// PaddingBottom declares the padding-bottom property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/padding-bottom
+func PaddingBottom(values ...Value) Prop {
+	return Declare("padding-bottom", values...)
+}
+
+// PaddingInline declares the padding-inline property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/padding-inline
+func PaddingInline(values ...Value) Prop {
+	return Declare("padding-inline", values...)
+}
+
+// PaddingLeft declares the padding-left property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/padding-left
+func PaddingLeft(values ...Value) Prop {
+	return Declare("padding-left", values...)
+}
+
+// PaddingRight declares the padding-right property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/padding-right
+func PaddingRight(values ...Value) Prop {
+	return Declare("padding-right", values...)
+}
+
+// PaddingTop declares the padding-top property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/padding-top
+func PaddingTop(values ...Value) Prop {
+	return Declare("padding-top", values...)
+}
+
+// PointerEvents declares the pointer-events property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/pointer-events
+func PointerEvents(values ...Value) Prop {
+	return Declare("pointer-events", values...)
+}
+
+// Position declares the position property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/position
+func Position(values ...Value) Prop {
+	return Declare("position", values...)
+}
+
+// Right declares the right property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/right
+func Right(values ...Value) Prop {
+	return Declare("right", values...)
+}
+
+// RowGap declares the row-gap property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/row-gap
+func RowGap(values ...Value) Prop {
+	return Declare("row-gap", values...)
+}
+
+// TextAlign declares the text-align property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/text-align
+func TextAlign(values ...Value) Prop {
+	return Declare("text-align", values...)
+}
+
+// TextDecoration declares the text-decoration property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/text-decoration
+func TextDecoration(values ...Value) Prop {
+	return Declare("text-decoration", values...)
+}
+
+// TextOverflow declares the text-overflow property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/text-overflow
+func TextOverflow(values ...Value) Prop {
+	return Declare("text-overflow", values...)
+}
+
+// TextTransform declares the text-transform property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/text-transform
+func TextTransform(values ...Value) Prop {
+	return Declare("text-transform", values...)
+}
+
+// Top declares the top property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/top
+func Top(values ...Value) Prop {
+	return Declare("top", values...)
+}
+
+// Transform declares the transform property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/transform
+func Transform(values ...Value) Prop {
+	return Declare("transform", values...)
+}
+
+// Transition declares the transition property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/transition
+func Transition(values ...Value) Prop {
+	return Declare("transition", values...)
+}
+
+// VerticalAlign declares the vertical-align property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/vertical-align
+func VerticalAlign(values ...Value) Prop {
+	return Declare("vertical-align", values...)
+}
+
+// Visibility declares the visibility property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/visibility
+func Visibility(values ...Value) Prop {
+	return Declare("visibility", values...)
+}
+
+// WhiteSpace declares the white-space property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/white-space
+func WhiteSpace(values ...Value) Prop {
+	return Declare("white-space", values...)
+}
+
+// Width declares the width property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/width
+func Width(values ...Value) Prop {
+	return Declare("width", values...)
+}
+
+// WordBreak declares the word-break property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/word-break
+func WordBreak(values ...Value) Prop {
+	return Declare("word-break", values...)
+}
+
+// ZIndex declares the z-index property.
+//
+// https://developer.mozilla.org/en-US/docs/Web/CSS/z-index
+func ZIndex(values ...Value) Prop {
+	return Declare("z-index", values...)
+}
@@ -0,0 +1,201 @@
+// Package css builds CSS in Go and scopes component styles to the elements
+// of the component.
+//
+// Props is a typed list of declarations, for style attributes, and
+// Stylesheet builds the content of Style elements, with nested rules, media
+// queries and keyframes:
+//
+//	g.Div(g.KV{"style": css.Props{css.Display("flex"), css.Margin(css.Rem(1))}})
+//	g.Style(css.Stylesheet{css.Rule("a", css.Props{css.Color("blue")})})
+//
+// A component declares its CSS once, with Scoped, and applies the scope to
+// its root element. The CSS of every scope used by the page is written once
+// into its Head, whatever renders it:
+//
+//	var cardStyle = css.Scoped(`
+//		& { padding: 1rem; border: 1px solid #ddd }
+//		h2 { margin: 0 }
+//	`)
+//
+//	func Card(title string, body g.Node) g.Node {
+//		return cardStyle.Apply(g.Div(g.H2(g.Text(title)), body))
+//	}
+package css
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+
+	"github.com/assaidy/g"
+	"github.com/assaidy/g/internal/cssscan"
+)
+
+// Scope is a set of CSS rules scoped to the elements of a component.
+type Scope struct {
+	// Class is the class of the root elements of the component, derived
+	// from the hash of the rules.
+	Class string
+	css   string
+	style g.Node // HeadItem holding the CSS
+}
+
+// Scoped returns the scope of rules, a style sheet whose selectors are
+// relative to the root element of a component.
+//
+// Selectors match the descendants of the root, and & stands for the root
+// itself: "h2" becomes ".css-1a2b3c4d h2" and "&:hover > a" becomes
+// ".css-1a2b3c4d:hover > a". Rules in @media, @supports, @container and
+// @layer blocks are scoped too; other at-rules, such as @keyframes, are kept
+// as is.
+//
+// Scopes don't stop at nested components: "h2" also matches the headings
+// of the components inside the root.
+func Scoped(rules string) *Scope {
+	h := fnv.New32a()
+	h.Write([]byte(rules))
+	class := fmt.Sprintf("css-%08x", h.Sum32())
+	css := scope(rules, "."+class)
+	return &Scope{Class: class, css: css, style: g.HeadItem(g.Style(cssscan.Raw(css)))}
+}
+
+// CSS returns the scoped style sheet.
+func (me *Scope) CSS() string {
+	return me.css
+}
+
+// Apply adds the class of the scope to e, the root element of a component,
+// and returns it. The existing class attribute of e is kept.
+//
+// The CSS of the scope is added to the children of e as a g.HeadItem, so
+// that rendering the document writes it once in Head, and rendering a
+// fragment without Head, such as an htmx response, writes it in e. The
+// children of void elements aren't rendered, so the root of a component
+// must not be one, such as an Input.
+//
+// Example:
+//
+//	cardStyle.Apply(g.Div(g.KV{"class": "card"}, children...))
+//	// <div class="card css-1a2b3c4d">...</div>
+func (me *Scope) Apply(e *g.Element) *g.Element {
+	if e.Attrs == nil {
+		e.Attrs = g.KV{}
+	}
+	c, ok := e.Attrs["class"].(*class)
+	if !ok {
+		c = &class{base: e.Attrs["class"]}
+		e.Attrs["class"] = c
+	}
+	if !slices.Contains(c.scopes, me) {
+		c.scopes = append(c.scopes, me)
+		e.Children = append(e.Children, me.style)
+	}
+	return e
+}
+
+// class is the value of the class attribute of a root element: its own
+// classes, and those of its scopes.
+type class struct {
+	base   any // string, AttrValuer, or nil
+	scopes []*Scope
+}
+
+func (me *class) AttrValue() (string, error) {
+	var classes []string
+	switch v := me.base.(type) {
+	case string:
+		classes = append(classes, v)
+	case g.AttrValuer:
+		s, err := v.AttrValue()
+		if err != nil {
+			return "", err
+		}
+		classes = append(classes, s)
+	}
+	for _, s := range me.scopes {
+		classes = append(classes, s.Class)
+	}
+	return strings.TrimSpace(strings.Join(classes, " ")), nil
+}
+
+// scopedAtRules are the at-rules holding rules, which are scoped too.
+var scopedAtRules = []string{"@media", "@supports", "@container", "@layer"}
+
+// scope rewrites the selectors of the rules of css to be relative to root.
+func scope(css, root string) string {
+	css = cssscan.StripComments(css)
+	var lines []string
+	for i := 0; i < len(css); {
+		for i < len(css) && cssscan.IsSpace(css[i]) {
+			i++
+		}
+		if i == len(css) {
+			break
+		}
+
+		end := cssscan.Scan(css, i, "{;")
+		if end == len(css) || css[end] == ';' {
+			if s := strings.TrimSpace(css[i:min(end+1, len(css))]); s != "" {
+				lines = append(lines, s)
+			}
+			i = end + 1
+			continue
+		}
+		close := cssscan.BlockEnd(css, end)
+		prelude := strings.Join(strings.Fields(css[i:end]), " ")
+		block := strings.TrimSpace(css[end+1 : min(close, len(css))])
+		i = close + 1
+
+		if strings.HasPrefix(prelude, "@") {
+			name, _, _ := strings.Cut(prelude, " ")
+			if slices.Contains(scopedAtRules, strings.ToLower(name)) {
+				block = indent(scope(block, root))
+				lines = append(lines, prelude+" {\n"+block+"\n}")
+			} else {
+				lines = append(lines, prelude+" { "+block+" }")
+			}
+			continue
+		}
+
+		var selectors []string
+		for j := 0; j < len(prelude); {
+			end := cssscan.Scan(prelude, j, ",")
+			selectors = append(selectors, scopeSelector(strings.TrimSpace(prelude[j:end]), root))
+			j = end + 1
+		}
+		lines = append(lines, strings.Join(selectors, ", ")+" { "+block+" }")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// scopeSelector makes sel relative to root, replacing & with root or
+// prefixing sel with it.
+func scopeSelector(sel, root string) string {
+	var sb strings.Builder
+	nested := false
+	for i := 0; i < len(sel); i++ {
+		switch sel[i] {
+		case '\\':
+			sb.WriteString(sel[i:min(i+2, len(sel))])
+			i++
+		case '"', '\'':
+			end := min(cssscan.StringEnd(sel, i)+1, len(sel))
+			sb.WriteString(sel[i:end])
+			i = end - 1
+		case '&':
+			sb.WriteString(root)
+			nested = true
+		default:
+			sb.WriteByte(sel[i])
+		}
+	}
+	if nested {
+		return sb.String()
+	}
+	return root + " " + sel
+}
+
+func indent(s string) string {
+	return "\t" + strings.ReplaceAll(s, "\n", "\n\t")
+}
@@ -0,0 +1,110 @@
+package css
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+func TestScoped(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    string
+		expected string
+	}{
+		{
+			name:     "Descendant selectors",
+			rules:    "h2, p > a { margin: 0 }",
+			expected: ".X h2, .X p > a { margin: 0 }",
+		},
+		{
+			name:     "Root selector",
+			rules:    "& { padding: 1rem }\n&:hover > a, :is(&.active) b { color: red }",
+			expected: ".X { padding: 1rem }\n.X:hover > a, :is(.X.active) b { color: red }",
+		},
+		{
+			name:     "Strings and comments",
+			rules:    `/* & { x: y } */ a[title="&,"] { content: "{&}" }`,
+			expected: `.X a[title="&,"] { content: "{&}" }`,
+		},
+		{
+			name:     "Media queries are scoped",
+			rules:    "@media (max-width: 600px) { & { padding: 0 } p { margin: 0 } }",
+			expected: "@media (max-width: 600px) {\n\t.X { padding: 0 }\n\t.X p { margin: 0 }\n}",
+		},
+		{
+			name:     "Keyframes are kept",
+			rules:    "@keyframes spin { from { rotate: 0 } to { rotate: 1turn } } & { animation: spin 1s }",
+			expected: "@keyframes spin { from { rotate: 0 } to { rotate: 1turn } }\n.X { animation: spin 1s }",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Scoped(tt.rules)
+			if !strings.HasPrefix(s.Class, "css-") {
+				t.Errorf("Scoped().Class = %q, want a css- prefix", s.Class)
+			}
+			if got := strings.ReplaceAll(s.CSS(), "."+s.Class, ".X"); got != tt.expected {
+				t.Errorf("Scoped().CSS() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScoped_Class(t *testing.T) {
+	a, b := Scoped("p { color: red }"), Scoped("p { color: blue }")
+	if a.Class == b.Class {
+		t.Errorf("Scoped() classes of different rules are both %q", a.Class)
+	}
+	if again := Scoped("p { color: red }"); again.Class != a.Class {
+		t.Errorf("Scoped() class = %q, then %q for the same rules", a.Class, again.Class)
+	}
+}
+
+func TestScope_Apply(t *testing.T) {
+	s := Scoped("p { margin: 0 }")
+	style := `<style>.` + s.Class + ` p { margin: 0 }</style>`
+	tests := []struct {
+		name     string
+		element  *g.Element
+		expected string
+	}{
+		{name: "No class", element: g.Div(), expected: `<div class="` + s.Class + `">` + style + `</div>`},
+		{name: "Existing class", element: g.Div(g.KV{"class": "card"}), expected: `<div class="card ` + s.Class + `">` + style + `</div>`},
+		{name: "Applied twice", element: s.Apply(g.Div()), expected: `<div class="` + s.Class + `">` + style + `</div>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.Apply(tt.element).Render()
+			if err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Apply() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScope_Apply_Head(t *testing.T) {
+	card := Scoped("& > h2 { margin: 0 }")
+	button := Scoped("& { color: red }")
+	Card := func(title string) g.Node {
+		return card.Apply(g.Div(g.H2(g.Text(title)), button.Apply(g.Button(g.Text("OK")))))
+	}
+
+	page := g.Html(g.Head(g.Title(g.Text("T"))), g.Body(Card("a"), Card("b")))
+	var sb strings.Builder
+	if err := g.Render(&sb, page); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	want := `<html><head><title>T</title><style>.` + button.Class + ` { color: red }</style><style>.` + card.Class + ` > h2 { margin: 0 }</style></head>` +
+		`<body><div class="` + card.Class + `"><h2>a</h2><button class="` + button.Class + `">OK</button></div>` +
+		`<div class="` + card.Class + `"><h2>b</h2><button class="` + button.Class + `">OK</button></div></body></html>`
+	if sb.String() != want {
+		t.Errorf("Render() = %q, want %q", sb.String(), want)
+	}
+}
@@ -0,0 +1,230 @@
+package css
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/assaidy/g/internal/cssscan"
+)
+
+// Item is a part of a Stylesheet or of the body of a rule: Props, Rule,
+// Media, Supports or Keyframes.
+type Item interface {
+	write(w *sheetWriter, parents []string) error
+}
+
+// Stylesheet is a list of rules. It renders as the content of a Style
+// element, and nested rules are flattened, so the output works in every
+// browser.
+//
+// Example:
+//
+//	g.Style(css.Stylesheet{
+//		css.Rule(".card",
+//			css.Props{css.Padding(css.Rem(1))},
+//			css.Rule("& > h2", css.Props{css.Margin("0")}),
+//			css.Media("(max-width: 600px)", css.Props{css.Padding("0")}),
+//		),
+//	})
+//	// .card { padding: 1rem }
+//	// .card > h2 { margin: 0 }
+//	// @media (max-width: 600px) {
+//	// 	.card { padding: 0 }
+//	// }
+type Stylesheet []Item
+
+// CSS returns the source of the style sheet.
+func (me Stylesheet) CSS() (string, error) {
+	w := &sheetWriter{}
+	if err := writeItems(w, me, nil); err != nil {
+		return "", err
+	}
+	return strings.Join(w.lines, "\n"), nil
+}
+
+// Render implements g.Node, writing the CSS unescaped, as browsers read
+// the content of a Style element.
+func (me Stylesheet) Render() (string, error) {
+	s, err := me.CSS()
+	if err != nil {
+		return "", err
+	}
+	return cssscan.Raw(s).Render()
+}
+
+type sheetWriter struct {
+	lines  []string
+	indent string
+}
+
+func (me *sheetWriter) line(s string) {
+	me.lines = append(me.lines, me.indent+s)
+}
+
+// block writes the lines of fn between "prelude {" and "}".
+func (me *sheetWriter) block(prelude string, fn func() error) error {
+	me.line(prelude + " {")
+	me.indent += "\t"
+	err := fn()
+	me.indent = me.indent[1:]
+	me.line("}")
+	return err
+}
+
+// writeItems writes the declarations of items as a rule for the parents
+// selectors, and then the other items.
+func writeItems(w *sheetWriter, items []Item, parents []string) error {
+	var decls []string
+	for _, item := range items {
+		if props, ok := item.(Props); ok {
+			for _, p := range props {
+				s, err := p.css()
+				if err != nil {
+					return err
+				}
+				decls = append(decls, s)
+			}
+		}
+	}
+	if len(decls) > 0 {
+		if parents == nil {
+			return fmt.Errorf("declarations outside of a rule: %s", strings.Join(decls, "; "))
+		}
+		w.line(strings.Join(parents, ", ") + " { " + strings.Join(decls, "; ") + " }")
+	}
+
+	for _, item := range items {
+		if err := item.write(w, parents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (me Props) write(*sheetWriter, []string) error {
+	return nil // written by writeItems
+}
+
+type rule struct {
+	selector string
+	body     []Item
+}
+
+// Rule returns a style rule. Its body holds Props, and nested rules and
+// at-rules, which apply relative to selector: & stands for the elements
+// selector matches, and selectors without & match their descendants.
+//
+// Example:
+//
+//	css.Rule("a", css.Props{css.Color("blue")}, css.Rule("&:hover", css.Props{css.Color("red")}))
+//	// a { color: blue }
+//	// a:hover { color: red }
+func Rule(selector string, body ...Item) Item {
+	return &rule{selector: selector, body: body}
+}
+
+func (me *rule) write(w *sheetWriter, parents []string) error {
+	if err := check(me.selector); err != nil {
+		return fmt.Errorf("selector: %w", err)
+	}
+	var selectors []string
+	for i := 0; i < len(me.selector); {
+		end := cssscan.Scan(me.selector, i, ",")
+		selectors = append(selectors, strings.TrimSpace(me.selector[i:end]))
+		i = end + 1
+	}
+	if parents != nil {
+		var nested []string
+		for _, parent := range parents {
+			for _, sel := range selectors {
+				nested = append(nested, nest(sel, parent))
+			}
+		}
+		selectors = nested
+	}
+	return writeItems(w, me.body, selectors)
+}
+
+// nest returns sel relative to parent.
+func nest(sel, parent string) string {
+	if strings.Contains(sel, "&") {
+		return scopeSelector(sel, parent)
+	}
+	return parent + " " + sel
+}
+
+type atRule struct {
+	name    string
+	prelude string
+	body    []Item
+}
+
+// Media returns a @media rule. In the body of a rule, its Props apply to
+// the elements of the rule.
+//
+// Example:
+//
+//	css.Media("(prefers-color-scheme: dark)", css.Rule("body", css.Props{css.Background("#000")}))
+func Media(query string, body ...Item) Item {
+	return &atRule{name: "@media", prelude: query, body: body}
+}
+
+// Supports returns a @supports rule, applying body when the browser
+// supports condition.
+func Supports(condition string, body ...Item) Item {
+	return &atRule{name: "@supports", prelude: condition, body: body}
+}
+
+func (me *atRule) write(w *sheetWriter, parents []string) error {
+	if err := check(me.prelude); err != nil {
+		return fmt.Errorf("%s: %w", me.name, err)
+	}
+	return w.block(me.name+" "+me.prelude, func() error {
+		return writeItems(w, me.body, parents)
+	})
+}
+
+// Frame is a step of a Keyframes animation.
+type Frame struct {
+	Selector string // "from", "to", or percentages such as "50%"
+	Props    Props
+}
+
+type keyframes struct {
+	name   string
+	frames []Frame
+}
+
+var identifier = regexp.MustCompile(`^-?[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// Keyframes returns a @keyframes rule defining the animation name.
+//
+// Example:
+//
+//	css.Keyframes("spin",
+//		css.Frame{"from", css.Props{css.Transform("rotate(0)")}},
+//		css.Frame{"to", css.Props{css.Transform("rotate(1turn)")}},
+//	)
+func Keyframes(name string, frames ...Frame) Item {
+	return &keyframes{name: name, frames: frames}
+}
+
+func (me *keyframes) write(w *sheetWriter, _ []string) error {
+	if !identifier.MatchString(me.name) {
+		return fmt.Errorf("invalid @keyframes name %q", me.name)
+	}
+	return w.block("@keyframes "+me.name, func() error {
+		for _, f := range me.frames {
+			if err := check(f.Selector); err != nil {
+				return fmt.Errorf("@keyframes %s: %w", me.name, err)
+			}
+			decls, err := f.Props.AttrValue()
+			if err != nil {
+				return err
+			}
+			w.line(f.Selector + " { " + decls + " }")
+		}
+		return nil
+	})
+}
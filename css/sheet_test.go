@@ -0,0 +1,109 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+func TestStylesheet_CSS(t *testing.T) {
+	tests := []struct {
+		name     string
+		sheet    Stylesheet
+		expected string
+		err      string
+	}{
+		{
+			name: "Rules",
+			sheet: Stylesheet{
+				Rule("body", Props{Margin("0"), FontFamily(List("Arial", "sans-serif"))}),
+				Rule("h1, h2", Props{Color("#333")}),
+			},
+			expected: "body { margin: 0; font-family: Arial, sans-serif }\nh1, h2 { color: #333 }",
+		},
+		{
+			name: "Nesting",
+			sheet: Stylesheet{
+				Rule(".card, .panel",
+					Props{Padding(Rem(1))},
+					Rule("& > h2, p", Props{Margin("0")}),
+					Rule("&:hover", Rule("a", Props{Color("red")})),
+				),
+			},
+			expected: ".card, .panel { padding: 1rem }\n" +
+				".card > h2, .card p, .panel > h2, .panel p { margin: 0 }\n" +
+				".card:hover a, .panel:hover a { color: red }",
+		},
+		{
+			name: "Media queries",
+			sheet: Stylesheet{
+				Rule(".nav",
+					Props{Display("flex")},
+					Media("(max-width: 600px)", Props{Display("block")}, Rule("a", Props{Padding(Px(4))})),
+				),
+				Media("print", Rule(".nav", Props{Display("none")})),
+			},
+			expected: ".nav { display: flex }\n" +
+				"@media (max-width: 600px) {\n\t.nav { display: block }\n\t.nav a { padding: 4px }\n}\n" +
+				"@media print {\n\t.nav { display: none }\n}",
+		},
+		{
+			name: "Keyframes and supports",
+			sheet: Stylesheet{
+				Keyframes("spin",
+					Frame{"from", Props{Transform("rotate(0)")}},
+					Frame{"to", Props{Transform("rotate(" + Deg(360) + ")")}},
+				),
+				Supports("(display: grid)", Rule(".grid", Props{Display("grid"), Animation("spin", S(1))})),
+			},
+			expected: "@keyframes spin {\n\tfrom { transform: rotate(0) }\n\tto { transform: rotate(360deg) }\n}\n" +
+				"@supports (display: grid) {\n\t.grid { display: grid; animation: spin 1s }\n}",
+		},
+		{
+			name:  "Declarations outside of a rule",
+			sheet: Stylesheet{Props{Color("red")}},
+			err:   "declarations outside of a rule: color: red",
+		},
+		{
+			name:  "Invalid selector",
+			sheet: Stylesheet{Rule("a { color: red } b", Props{Color("red")})},
+			err:   `selector: unexpected '{' in "a { color: red } b"`,
+		},
+		{
+			name:  "Invalid keyframes name",
+			sheet: Stylesheet{Keyframes("a b")},
+			err:   `invalid @keyframes name "a b"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.sheet.CSS()
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("CSS() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CSS() error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("CSS() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStylesheet_Render(t *testing.T) {
+	result, err := g.Style(Stylesheet{
+		Rule("ul > li", Props{Content(String("</style><script>"))}),
+	}).Render()
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	want := `<style>ul > li { content: "\3c /style\3e \3c script\3e " }</style>`
+	if result != want {
+		t.Errorf("Render() = %q, want %q", result, want)
+	}
+}
@@ -0,0 +1,228 @@
+package css
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/assaidy/g/internal/cssscan"
+)
+
+// Value is a CSS value, such as "flex", "1rem" or "1px solid red". It is
+// written as is, so it must not come from users; use String and URL for
+// user data. Values that would end the declaration or the rule they are
+// in, such as "red; x: y", make the rendering fail.
+type Value string
+
+// Prop is a CSS declaration, such as "display: flex".
+type Prop struct {
+	Name      string
+	Value     Value
+	Important bool
+}
+
+// Declare returns the declaration of any property, such as a custom
+// property, for those without a constructor.
+//
+// Example:
+//
+//	css.Declare("--gap", css.Rem(1))
+func Declare(name string, values ...Value) Prop {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = string(v)
+	}
+	return Prop{Name: name, Value: Value(strings.Join(parts, " "))}
+}
+
+// Important returns p with the !important flag.
+func Important(p Prop) Prop {
+	p.Important = true
+	return p
+}
+
+var propName = regexp.MustCompile(`^(--[A-Za-z0-9_-]+|-?[A-Za-z][A-Za-z0-9-]*)$`)
+
+func (me Prop) css() (string, error) {
+	if !propName.MatchString(me.Name) {
+		return "", fmt.Errorf("invalid CSS property name %q", me.Name)
+	}
+	if err := check(string(me.Value)); err != nil {
+		return "", fmt.Errorf("property %s: %w", me.Name, err)
+	}
+	s := me.Name + ": " + strings.TrimSpace(string(me.Value))
+	if me.Important {
+		s += " !important"
+	}
+	return s, nil
+}
+
+// Props is a list of declarations. It is a valid value of the style
+// attribute, and the body of a Rule.
+//
+// Example:
+//
+//	g.Div(g.KV{"style": css.Props{css.Display("flex"), css.Margin(css.Rem(1))}})
+//	// <div style="display: flex; margin: 1rem"></div>
+type Props []Prop
+
+// AttrValue implements g.AttrValuer.
+func (me Props) AttrValue() (string, error) {
+	parts := make([]string, len(me))
+	for i, p := range me {
+		s, err := p.css()
+		if err != nil {
+			return "", err
+		}
+		parts[i] = s
+	}
+	return strings.Join(parts, "; "), nil
+}
+
+func number(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// Num returns a number without a unit, e.g., for line-height.
+func Num(n float64) Value {
+	return Value(number(n))
+}
+
+// Px returns a length in pixels.
+func Px(n float64) Value {
+	return Value(number(n) + "px")
+}
+
+// Rem returns a length relative to the font size of the root element.
+func Rem(n float64) Value {
+	return Value(number(n) + "rem")
+}
+
+// Em returns a length relative to the font size of the element.
+func Em(n float64) Value {
+	return Value(number(n) + "em")
+}
+
+// Percent returns a percentage.
+func Percent(n float64) Value {
+	return Value(number(n) + "%")
+}
+
+// Vw returns a length relative to the width of the viewport.
+func Vw(n float64) Value {
+	return Value(number(n) + "vw")
+}
+
+// Vh returns a length relative to the height of the viewport.
+func Vh(n float64) Value {
+	return Value(number(n) + "vh")
+}
+
+// Deg returns an angle in degrees.
+func Deg(n float64) Value {
+	return Value(number(n) + "deg")
+}
+
+// Ms returns a duration in milliseconds.
+func Ms(n float64) Value {
+	return Value(number(n) + "ms")
+}
+
+// S returns a duration in seconds.
+func S(n float64) Value {
+	return Value(number(n) + "s")
+}
+
+// Var returns a reference to the custom property name, with an optional
+// fallback.
+//
+// Example:
+//
+//	css.Var("--gap", css.Rem(1)) // var(--gap, 1rem)
+func Var(name string, fallback ...Value) Value {
+	s := "var(" + name
+	for _, v := range fallback {
+		s += ", " + string(v)
+	}
+	return Value(s + ")")
+}
+
+// List returns values separated by commas, e.g., for font-family or
+// transition.
+func List(values ...Value) Value {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = string(v)
+	}
+	return Value(strings.Join(parts, ", "))
+}
+
+// String returns s as a quoted CSS string, escaped so that any s is safe,
+// e.g., for content or font-family.
+//
+// Example:
+//
+//	css.Content(css.String(`say "hi"`)) // content: "say \"hi\""
+func String(s string) Value {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r < 0x20 || r == 0x7f || r == '<' || r == '>' || r == '&':
+			// control characters can't be written in strings, and the
+			// others could close the style element
+			fmt.Fprintf(&sb, "\\%x ", r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return Value(sb.String())
+}
+
+// URL returns u as a url() value, escaped so that any u is safe. It doesn't
+// check the scheme of u.
+func URL(u string) Value {
+	return Value("url(" + string(String(u)) + ")")
+}
+
+// check reports an error when s, a value, selector or prelude, would end
+// the declaration or rule it is in: a ; { or } outside strings and
+// parentheses, an unterminated string or unbalanced parentheses.
+func check(s string) error {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			i++
+		case '"', '\'':
+			end := cssscan.StringEnd(s, i)
+			if end == len(s) || s[end] != c {
+				return fmt.Errorf("unterminated string in %q", s)
+			}
+			i = end
+		case '(', '[':
+			depth++
+		case ')', ']':
+			if depth--; depth < 0 {
+				return fmt.Errorf("unbalanced %q in %q", c, s)
+			}
+		case ';', '{', '}':
+			if depth == 0 {
+				return fmt.Errorf("unexpected %q in %q", c, s)
+			}
+		case '<':
+			if strings.HasPrefix(s[i:], "</") || strings.HasPrefix(s[i:], "<!--") {
+				return fmt.Errorf("unexpected %q in %q", s[i:i+2], s)
+			}
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("unbalanced parentheses in %q", s)
+	}
+	return nil
+}
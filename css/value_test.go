@@ -0,0 +1,86 @@
+package css
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+func TestProps_AttrValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		props    Props
+		expected string
+		err      string
+	}{
+		{
+			name:     "Declarations",
+			props:    Props{Display("flex"), Margin(Rem(1), Px(2.5)), LineHeight(Num(1.5)), Important(Color("red"))},
+			expected: "display: flex; margin: 1rem 2.5px; line-height: 1.5; color: red !important",
+		},
+		{
+			name:     "Functions and lists",
+			props:    Props{Declare("--gap", Rem(1)), Gap(Var("--gap", Px(8))), Transition(List("opacity 200ms", "transform "+S(1))), Width("calc(100% - 2rem)")},
+			expected: "--gap: 1rem; gap: var(--gap, 8px); transition: opacity 200ms, transform 1s; width: calc(100% - 2rem)",
+		},
+		{
+			name:     "Strings and URLs",
+			props:    Props{Content(String("say \"hi\"\\\n</style>")), BackgroundImage(URL("/a b.png?x=(1)"))},
+			expected: `content: "say \"hi\"\\\a \3c /style\3e "; background-image: url("/a b.png?x=(1)")`,
+		},
+		{
+			name:  "Value ending the declaration",
+			props: Props{Color("red; background: url(x)")},
+			err:   `property color: unexpected ';' in "red; background: url(x)"`,
+		},
+		{
+			name:  "Value closing the style element",
+			props: Props{Color("red</style>")},
+			err:   `property color: unexpected "</" in "red</style>"`,
+		},
+		{
+			name:  "Unterminated string",
+			props: Props{FontFamily(`"Arial`)},
+			err:   `property font-family: unterminated string in "\"Arial"`,
+		},
+		{
+			name:  "Invalid property name",
+			props: Props{Declare("color:red;x", "y")},
+			err:   `invalid CSS property name "color:red;x"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.props.AttrValue()
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("AttrValue() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AttrValue() error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("AttrValue() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProps_StyleAttribute(t *testing.T) {
+	result, err := g.Div(g.KV{"style": Props{FontFamily(List(String(`"Quoted" & Co`), "serif"))}}).Render()
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	want := `<div style="font-family: &#34;\&#34;Quoted\&#34; \26  Co&#34;, serif"></div>`
+	if result != want {
+		t.Errorf("Render() = %q, want %q", result, want)
+	}
+
+	if _, err := g.Div(g.KV{"style": Props{Color("x;y")}}).Render(); err == nil || !strings.Contains(err.Error(), "unexpected ';'") {
+		t.Errorf("Render() error = %v, want an unexpected ';' error", err)
+	}
+}
@@ -0,0 +1,354 @@
+package g
+
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"slices"
+	"strings"
+)
+
+// PatchOp identifies the kind of change a Patch describes.
+type PatchOp string
+
+const (
+	// PatchReplace replaces the node at Path with Node.
+	PatchReplace PatchOp = "replace"
+	// PatchInsert inserts Node so that it becomes the child at Path.
+	PatchInsert PatchOp = "insert"
+	// PatchRemove removes the node at Path.
+	PatchRemove PatchOp = "remove"
+	// PatchMove moves the node at Path so that it becomes child number To
+	// of the same parent (the index is counted after the node is taken out).
+	PatchMove PatchOp = "move"
+	// PatchSetText sets the content of the text node at Path to Value.
+	PatchSetText PatchOp = "set-text"
+	// PatchSetAttr sets attribute Name of the element at Path to Value.
+	PatchSetAttr PatchOp = "set-attr"
+	// PatchRemoveAttr removes attribute Name from the element at Path.
+	PatchRemoveAttr PatchOp = "remove-attr"
+)
+
+// Patch is a single operation that transforms a rendered tree.
+//
+// Path is a list of child indexes, starting from the container the tree is
+// rendered into. Indexes count DOM nodes: tagless elements (e.g., Empty(),
+// utils.Map()) are flattened into their parent and adjacent Text nodes are
+// merged, exactly as the browser sees the rendered HTML.
+//
+// Patches must be applied in order, since every Path refers to the tree as
+// left by the previous patches.
+//
+// A Patch marshals to JSON with Node rendered into an "html" field, so the
+// list returned by Diff can be sent to a client as is.
+type Patch struct {
+	Op    PatchOp
+	Path  []int
+	Name  string // attribute name, for PatchSetAttr and PatchRemoveAttr
+	Value string // text or attribute value, for PatchSetText and PatchSetAttr
+	To    int    // destination index, for PatchMove
+	Node  Node   // new content, for PatchReplace and PatchInsert
+
+	renderer *renderer // renders Node as part of the new tree
+}
+
+// MarshalJSON renders Node into HTML and encodes the patch as a JSON object.
+// Rendering errors are returned as marshaling errors.
+func (me Patch) MarshalJSON() ([]byte, error) {
+	type jsonPatch struct {
+		Op    PatchOp `json:"op"`
+		Path  []int   `json:"path"`
+		Name  string  `json:"name,omitempty"`
+		Value string  `json:"value,omitempty"`
+		To    int     `json:"to,omitempty"`
+		HTML  string  `json:"html,omitempty"`
+	}
+	p := jsonPatch{Op: me.Op, Path: me.Path, Name: me.Name, Value: me.Value, To: me.To}
+	if p.Path == nil {
+		p.Path = []int{}
+	}
+	if me.Node != nil {
+		r := me.renderer
+		if r == nil {
+			r = newRenderer(InDocument(me.Node))
+		}
+		s, err := r.renderNode(me.Node)
+		if err != nil {
+			return nil, err
+		}
+		p.HTML = s
+	}
+	return json.Marshal(p)
+}
+
+// keyAttr is the attribute used to match children across renders.
+const keyAttr = "key"
+
+// Diff computes the patches that turn the rendered output of old into the
+// rendered output of new.
+//
+// Elements with the same tag are patched in place (attributes and children),
+// text nodes get their content replaced, and anything else is replaced as a
+// whole. When every child of a parent, in both trees, is an element with a
+// unique "key" attribute, children are matched by key, so reordering a list
+// built with utils.Map() produces moves instead of rewriting every item.
+//
+// Nodes that are neither Text nor *Element are compared by their rendered
+// output and are treated as a single DOM node. UniqueIDs get the values
+// they have when each tree is rendered as a whole.
+//
+// Example:
+//
+//	patches := Diff(
+//		Ul(Li(KV{"key": "a"}, Text("A")), Li(KV{"key": "b"}, Text("B"))),
+//		Ul(Li(KV{"key": "b"}, Text("B")), Li(KV{"key": "a"}, Text("A"))),
+//	)
+//	// [{Op: "move", Path: [0 0], To: 1}]
+func Diff(old, new Node) []Patch {
+	d := &differ{oldRenderer: newRenderer(InDocument(old)), newRenderer: newRenderer(InDocument(new))}
+	d.diffChildren(nil, Flatten([]Node{old}), Flatten([]Node{new}))
+	return d.patches
+}
+
+type differ struct {
+	patches     []Patch
+	oldRenderer *renderer
+	newRenderer *renderer
+}
+
+func (me *differ) add(p Patch) {
+	if p.Node != nil {
+		p.renderer = me.newRenderer
+	}
+	me.patches = append(me.patches, p)
+}
+
+func (me *differ) diffNode(path []int, old, new Node) {
+	switch o := old.(type) {
+	case Text:
+		if n, ok := new.(Text); ok {
+			os, _ := o.Render()
+			ns, _ := n.Render()
+			if os != ns {
+				me.add(Patch{Op: PatchSetText, Path: path, Value: html.UnescapeString(ns)})
+			}
+			return
+		}
+	case *Element:
+		if n, ok := new.(*Element); ok && o.Tag == n.Tag && o.IsVoid == n.IsVoid {
+			me.diffAttrs(path, o.Attrs, n.Attrs)
+			if !n.IsVoid {
+				me.diffChildren(path, Flatten(o.Children), Flatten(n.Children))
+			}
+			return
+		}
+	default:
+		if _, ok := new.(*Element); !ok {
+			if _, ok := new.(Text); !ok {
+				os, oerr := old.Render()
+				ns, nerr := new.Render()
+				if oerr == nil && nerr == nil && os == ns {
+					return
+				}
+			}
+		}
+	}
+	me.add(Patch{Op: PatchReplace, Path: path, Node: new})
+}
+
+func (me *differ) diffAttrs(path []int, old, new KV) {
+	// attributes that fail to render are left out, the tree can't be
+	// rendered with them anyway
+	oldAttrs, _ := renderedAttrs(old, me.oldRenderer)
+	newAttrs, _ := renderedAttrs(new, me.newRenderer)
+
+	keys := make([]string, 0, len(oldAttrs)+len(newAttrs))
+	for k := range oldAttrs {
+		keys = append(keys, k)
+	}
+	for k := range newAttrs {
+		if _, ok := oldAttrs[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		ov, inOld := oldAttrs[k]
+		nv, inNew := newAttrs[k]
+		switch {
+		case !inNew:
+			me.add(Patch{Op: PatchRemoveAttr, Path: path, Name: k})
+		case !inOld || ov != nv:
+			me.add(Patch{Op: PatchSetAttr, Path: path, Name: k, Value: nv})
+		}
+	}
+}
+
+func (me *differ) diffChildren(path []int, old, new []Node) {
+	if oldKeys, newKeys := childKeys(old), childKeys(new); oldKeys != nil && newKeys != nil {
+		me.diffKeyedChildren(path, old, new, oldKeys, newKeys)
+		return
+	}
+
+	common := min(len(old), len(new))
+	for i := range common {
+		me.diffNode(childPath(path, i), old[i], new[i])
+	}
+	for i := len(old) - 1; i >= common; i-- {
+		me.add(Patch{Op: PatchRemove, Path: childPath(path, i)})
+	}
+	for i := common; i < len(new); i++ {
+		me.add(Patch{Op: PatchInsert, Path: childPath(path, i), Node: new[i]})
+	}
+}
+
+// diffKeyedChildren matches children by key. Children that are part of the
+// longest subsequence keeping their relative order never move; every other
+// retained child is moved exactly once, which keeps the number of moves
+// minimal.
+func (me *differ) diffKeyedChildren(path []int, old, new []Node, oldKeys, newKeys []string) {
+	oldByKey := make(map[string]Node, len(old))
+	for i, k := range oldKeys {
+		oldByKey[k] = old[i]
+	}
+	newIndex := make(map[string]int, len(new))
+	for i, k := range newKeys {
+		newIndex[k] = i
+	}
+
+	// removals go from the end, so that earlier indexes stay valid
+	cur := slices.Clone(oldKeys)
+	for i := len(cur) - 1; i >= 0; i-- {
+		if _, ok := newIndex[cur[i]]; !ok {
+			me.add(Patch{Op: PatchRemove, Path: childPath(path, i)})
+			cur = slices.Delete(cur, i, i+1)
+		}
+	}
+
+	// anchored children are in their final relative order: the ones that
+	// never move, plus the ones that already moved.
+	anchored := stableKeys(cur, newIndex)
+
+	move := func(from, to int) {
+		me.add(Patch{Op: PatchMove, Path: childPath(path, from), To: to})
+		k := cur[from]
+		cur = slices.Delete(cur, from, from+1)
+		cur = slices.Insert(cur, to, k)
+		anchored[k] = true
+	}
+
+	for i, k := range newKeys {
+		// children standing in front of an anchored key belong further
+		// down, move them right before the first anchored child that
+		// follows them.
+		for i < len(cur) && cur[i] != k && !anchored[cur[i]] && anchored[k] {
+			to := len(cur) - 1
+			for j := i + 1; j < len(cur); j++ {
+				if anchored[cur[j]] && newIndex[cur[j]] > newIndex[cur[i]] {
+					to = j - 1
+					break
+				}
+			}
+			move(i, to)
+		}
+
+		oldNode, inOld := oldByKey[k]
+		switch {
+		case !inOld:
+			me.add(Patch{Op: PatchInsert, Path: childPath(path, i), Node: new[i]})
+			cur = slices.Insert(cur, i, k)
+			anchored[k] = true
+			continue
+		case cur[i] != k:
+			move(slices.Index(cur, k), i)
+		}
+		me.diffNode(childPath(path, i), oldNode, new[i])
+	}
+}
+
+// stableKeys returns the keys of cur that form the longest subsequence
+// already ordered as in newIndex.
+func stableKeys(cur []string, newIndex map[string]int) map[string]bool {
+	// patience sorting: tails[l] is the index in cur ending the best
+	// increasing subsequence of length l+1.
+	var tails []int
+	prev := make([]int, len(cur))
+	for i, k := range cur {
+		pos, _ := slices.BinarySearchFunc(tails, newIndex[k], func(t int, target int) int {
+			return newIndex[cur[t]] - target
+		})
+		if pos > 0 {
+			prev[i] = tails[pos-1]
+		} else {
+			prev[i] = -1
+		}
+		if pos == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[pos] = i
+		}
+	}
+
+	stable := make(map[string]bool, len(tails))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = prev[i] {
+			stable[cur[i]] = true
+		}
+	}
+	return stable
+}
+
+// childKeys returns the keys of the nodes, or nil unless every node is an
+// element with a unique, non-empty key.
+func childKeys(nodes []Node) []string {
+	if len(nodes) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(nodes))
+	seen := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		e, ok := node.(*Element)
+		if !ok {
+			return nil
+		}
+		k, ok := e.Attrs[keyAttr].(string)
+		if !ok || k == "" || seen[k] {
+			return nil
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// renderedAttrs returns the attributes as they end up in the HTML: boolean
+// attributes have an empty value and false ones are left out. Attributes
+// that fail to render are left out too, and their errors returned.
+func renderedAttrs(attrs KV, r *renderer) (map[string]string, error) {
+	result := make(map[string]string, len(attrs))
+	var errs []error
+	for key, value := range attrs {
+		k := strings.TrimSpace(key)
+		if !isValidAttrKey(k) {
+			continue
+		}
+		if v, ok := value.(bool); ok {
+			if v {
+				result[k] = ""
+			}
+			continue
+		}
+		s, err := r.attrString("", k, value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result[k] = s
+	}
+	return result, errors.Join(errs...)
+}
+
+func childPath(path []int, i int) []int {
+	return append(slices.Clip(path), i)
+}
@@ -0,0 +1,227 @@
+package g
+
+import (
+	"encoding/json"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	keyed := func(keys ...string) Node {
+		ul := Ul()
+		for _, k := range keys {
+			ul.Children = append(ul.Children, Li(KV{"key": k}, Text(k)))
+		}
+		return ul
+	}
+
+	tests := []struct {
+		name     string
+		old      Node
+		new      Node
+		expected string
+	}{
+		{
+			name:     "Identical trees",
+			old:      Div(KV{"class": "a"}, Text("Hello")),
+			new:      Div(KV{"class": "a"}, Text("Hello")),
+			expected: `[]`,
+		},
+		{
+			name:     "Text change",
+			old:      Div(Text("Hello")),
+			new:      Div(Text("Hello & bye")),
+			expected: `[{"op":"set-text","path":[0,0],"value":"Hello & bye"}]`,
+		},
+		{
+			name:     "Whitespace-only difference",
+			old:      P(Text("a  b")),
+			new:      P(Text("a b")),
+			expected: `[]`,
+		},
+		{
+			name:     "Attribute changes",
+			old:      Input(KV{"type": "text", "disabled": true, "value": "x"}),
+			new:      Input(KV{"type": "text", "disabled": false, "value": "y", "required": true}),
+			expected: `[{"op":"remove-attr","path":[0],"name":"disabled"},{"op":"set-attr","path":[0],"name":"required"},{"op":"set-attr","path":[0],"name":"value","value":"y"}]`,
+		},
+		{
+			name:     "Tag change replaces",
+			old:      Div(Span(Text("a"))),
+			new:      Div(Strong(Text("a"))),
+			expected: `[{"op":"replace","path":[0,0],"html":"<strong>a</strong>"}]`,
+		},
+		{
+			name:     "Appended children",
+			old:      Ul(Li(Text("1"))),
+			new:      Ul(Li(Text("1")), Li(Text("2"))),
+			expected: `[{"op":"insert","path":[0,1],"html":"<li>2</li>"}]`,
+		},
+		{
+			name:     "Removed children",
+			old:      Ul(Li(Text("1")), Li(Text("2")), Li(Text("3"))),
+			new:      Ul(Li(Text("1"))),
+			expected: `[{"op":"remove","path":[0,2]},{"op":"remove","path":[0,1]}]`,
+		},
+		{
+			name:     "Tagless containers are flattened",
+			old:      Div(Empty(Span(), Empty(Text("a"), Text("b")))),
+			new:      Div(Span(), Text("ab")),
+			expected: `[]`,
+		},
+		{
+			name:     "Keyed swap",
+			old:      keyed("a", "b"),
+			new:      keyed("b", "a"),
+			expected: `[{"op":"move","path":[0,0],"to":1}]`,
+		},
+		{
+			name:     "Keyed rotation moves one child",
+			old:      keyed("a", "b", "c", "d"),
+			new:      keyed("b", "c", "d", "a"),
+			expected: `[{"op":"move","path":[0,0],"to":3}]`,
+		},
+		{
+			name:     "Keyed insert and remove",
+			old:      keyed("a", "b", "c"),
+			new:      keyed("a", "x", "c"),
+			expected: `[{"op":"remove","path":[0,1]},{"op":"insert","path":[0,1],"html":"<li key=\"x\">x</li>"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := json.Marshal(Diff(tt.old, tt.new))
+			if err != nil {
+				t.Errorf("Diff() marshal error: %v", err)
+				return
+			}
+			got := jsonUnescaper.Replace(string(result))
+			if got == "null" {
+				got = "[]"
+			}
+			if got != tt.expected {
+				t.Errorf("Diff() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+// jsonUnescaper undoes the HTML escaping done by encoding/json, to keep the
+// expected values readable.
+var jsonUnescaper = strings.NewReplacer(`\u003c`, "<", `\u003e`, ">", `\u0026`, "&")
+
+func TestDiff_MarshalError(t *testing.T) {
+	patches := Diff(Div(), Div(Span(KV{"bad": nil})))
+	if _, err := json.Marshal(patches); err == nil {
+		t.Error("Patch.MarshalJSON() should return the render error")
+	}
+}
+
+func TestDiff_KeyedShuffles(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	pool := strings.Split("abcdefghijkl", "")
+
+	for i := range 200 {
+		oldKeys := slices.Clone(pool[:rnd.Intn(len(pool))])
+		rnd.Shuffle(len(oldKeys), func(i, j int) { oldKeys[i], oldKeys[j] = oldKeys[j], oldKeys[i] })
+		newKeys := slices.Clone(pool[:rnd.Intn(len(pool))])
+		rnd.Shuffle(len(newKeys), func(i, j int) { newKeys[i], newKeys[j] = newKeys[j], newKeys[i] })
+
+		build := func(keys []string, suffix string) Node {
+			ul := Ul()
+			for _, k := range keys {
+				ul.Children = append(ul.Children, Li(KV{"key": k}, Text(k+suffix)))
+			}
+			return ul
+		}
+		old, new := build(oldKeys, ""), build(newKeys, "!")
+
+		got := applyPatches(t, old, Diff(old, new))
+		want, _ := new.Render()
+		if got != want {
+			t.Errorf("case %d: applying Diff(%v, %v) = %q, want %q", i, oldKeys, newKeys, got, want)
+		}
+	}
+}
+
+// applyPatches applies patches to the rendered old tree through a minimal
+// DOM model and returns the resulting HTML.
+func applyPatches(t *testing.T, old Node, patches []Patch) string {
+	t.Helper()
+
+	type dom struct {
+		html     string // pre-rendered subtree, used for leaf nodes
+		tag      string
+		attrs    KV
+		children []*dom
+	}
+	var build func(n Node) *dom
+	build = func(n Node) *dom {
+		e, ok := n.(*Element)
+		if !ok {
+			s, _ := n.Render()
+			return &dom{html: s}
+		}
+		d := &dom{tag: e.Tag, attrs: KV{}}
+		for k, v := range e.Attrs {
+			d.attrs[k] = v
+		}
+		for _, c := range Flatten(e.Children) {
+			d.children = append(d.children, build(c))
+		}
+		return d
+	}
+	var render func(d *dom) string
+	render = func(d *dom) string {
+		if d.tag == "" {
+			return d.html
+		}
+		s, _ := (&Element{Tag: d.tag, Attrs: d.attrs}).Render()
+		var children strings.Builder
+		for _, c := range d.children {
+			children.WriteString(render(c))
+		}
+		return strings.Replace(s, "></", ">"+children.String()+"</", 1)
+	}
+
+	root := &dom{tag: "root"}
+	for _, c := range Flatten([]Node{old}) {
+		root.children = append(root.children, build(c))
+	}
+	for _, p := range patches {
+		parent := root
+		for _, i := range p.Path[:len(p.Path)-1] {
+			parent = parent.children[i]
+		}
+		i := p.Path[len(p.Path)-1]
+		switch p.Op {
+		case PatchReplace:
+			parent.children[i] = build(p.Node)
+		case PatchInsert:
+			parent.children = slices.Insert(parent.children, i, build(p.Node))
+		case PatchRemove:
+			parent.children = slices.Delete(parent.children, i, i+1)
+		case PatchMove:
+			d := parent.children[i]
+			parent.children = slices.Delete(parent.children, i, i+1)
+			parent.children = slices.Insert(parent.children, p.To, d)
+		case PatchSetText:
+			parent.children[i] = build(Text(p.Value))
+		case PatchSetAttr:
+			parent.children[i].attrs[p.Name] = p.Value
+		case PatchRemoveAttr:
+			delete(parent.children[i].attrs, p.Name)
+		default:
+			t.Fatalf("unknown patch op %q", p.Op)
+		}
+	}
+
+	var result strings.Builder
+	for _, c := range root.children {
+		result.WriteString(render(c))
+	}
+	return result.String()
+}
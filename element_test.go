@@ -1,8 +1,11 @@
 package g
 
 import (
+	"errors"
 	"strings"
 	"testing"
+
+	"golang.org/x/net/html"
 )
 
 func TestElement_Render(t *testing.T) {
@@ -92,6 +95,54 @@ func TestElement_Render(t *testing.T) {
 			expected: `<div class="test"></div>`,
 			wantErr:  false,
 		},
+		{
+			name:     "Code text keeps whitespace",
+			element:  Pre(CodeText("a\n  <b>")),
+			expected: "<pre>a\n  &lt;b&gt;</pre>",
+			wantErr:  false,
+		},
+		{
+			name:     "Custom element",
+			element:  CustomElement("my-widget", KV{"size": "2"}, Text("hi")),
+			expected: `<my-widget size="2">hi</my-widget>`,
+			wantErr:  false,
+		},
+		{
+			name:     "Custom element with non-ASCII name",
+			element:  CustomElement("math-α"),
+			expected: `<math-α></math-α>`,
+			wantErr:  false,
+		},
+		{
+			name:     "Custom element with attributes in the tag",
+			element:  CustomElement("x onload=alert(1)"),
+			expected: "",
+			wantErr:  true,
+		},
+		{
+			name:     "Custom element closing the tag",
+			element:  CustomElement("x><script>alert(1)</script"),
+			expected: "",
+			wantErr:  true,
+		},
+		{
+			name:     "Custom element starting with a digit",
+			element:  CustomElement("1-x"),
+			expected: "",
+			wantErr:  true,
+		},
+		{
+			name:     "Multiple attribute maps are merged",
+			element:  Div(KV{"class": "a", "id": "main"}, KV{"class": "b"}),
+			expected: `<div class="b" id="main"></div>`,
+			wantErr:  false,
+		},
+		{
+			name:     "Multiple attribute maps on void element",
+			element:  Input(KV{"type": "text"}, KV{"required": true}),
+			expected: `<input required type="text">`,
+			wantErr:  false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -108,6 +159,15 @@ func TestElement_Render(t *testing.T) {
 	}
 }
 
+func TestElement_MergedAttrsDontModifyArguments(t *testing.T) {
+	base := KV{"class": "a"}
+	Div(base, KV{"class": "b", "id": "x"})
+	Img(base, KV{"alt": ""})
+	if len(base) != 1 || base["class"] != "a" {
+		t.Errorf("argument KV = %v, want it unchanged", base)
+	}
+}
+
 func TestElement_Add(t *testing.T) {
 	// Test adding children to non-void element
 	div := Div()
@@ -175,6 +235,78 @@ func TestElement_renderAttrs(t *testing.T) {
 			expected:  "",
 			expectErr: true,
 		},
+		{
+			name:      "AttrValuer value",
+			attrs:     KV{"data-value": attrValue{value: "<ok>"}},
+			expected:  ` data-value="&lt;ok&gt;"`,
+			expectErr: false,
+		},
+		{
+			name:      "AttrValuer error",
+			attrs:     KV{"data-value": attrValue{err: errors.New("bad value")}},
+			expected:  "",
+			expectErr: true,
+		},
+		{
+			name:      "Key injecting an attribute",
+			attrs:     KV{`x="y" onload="evil`: "value"},
+			expected:  "",
+			expectErr: true,
+		},
+		{
+			name:      "Key closing the tag",
+			attrs:     KV{"a>b": "value"},
+			expected:  "",
+			expectErr: true,
+		},
+		{
+			name:      "Key with slash",
+			attrs:     KV{"a/b": true},
+			expected:  "",
+			expectErr: true,
+		},
+		{
+			name:      "Key with equals sign",
+			attrs:     KV{"a=b": "value"},
+			expected:  "",
+			expectErr: true,
+		},
+		{
+			name:      "Key with single quote",
+			attrs:     KV{"a'b": "value"},
+			expected:  "",
+			expectErr: true,
+		},
+		{
+			name:      "Key with inner space",
+			attrs:     KV{"a b": "value"},
+			expected:  "",
+			expectErr: true,
+		},
+		{
+			name:      "Key with control character",
+			attrs:     KV{"a\x00b": "value"},
+			expected:  "",
+			expectErr: true,
+		},
+		{
+			name:      "Key with noncharacter",
+			attrs:     KV{"a\uFFFEb": "value"},
+			expected:  "",
+			expectErr: true,
+		},
+		{
+			name:      "Key with colon, dash and dot",
+			attrs:     KV{"hx-on:htmx:after-swap": "x", "x.y": "z"},
+			expected:  ` hx-on:htmx:after-swap="x" x.y="z"`,
+			expectErr: false,
+		},
+		{
+			name:      "Key with surrounding whitespace",
+			attrs:     KV{" class ": "a"},
+			expected:  ` class="a"`,
+			expectErr: false,
+		},
 		{
 			name:      "Key with HTML escaping",
 			attrs:     KV{"data-value": "<script>"},
@@ -187,7 +319,7 @@ func TestElement_renderAttrs(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			element := &Element{Attrs: tt.attrs}
 			var builder strings.Builder
-			err := element.renderAttrs(&builder)
+			err := element.renderAttrs(&builder, newRenderer())
 
 			if (err != nil) != tt.expectErr {
 				t.Errorf("renderAttrs() error = %v, expectErr %v", err, tt.expectErr)
@@ -200,3 +332,70 @@ func TestElement_renderAttrs(t *testing.T) {
 		})
 	}
 }
+
+func TestElement_renderAttrs_ErrorIncludesTag(t *testing.T) {
+	_, err := Input(KV{"a>b": "x"}).Render()
+	if err == nil || !strings.Contains(err.Error(), "<input>") {
+		t.Errorf("Element.Render() error = %v, want it to mention <input>", err)
+	}
+}
+
+func FuzzElement_renderAttrs(f *testing.F) {
+	f.Add("class", "container")
+	f.Add(`x="y" onload="evil`, "")
+	f.Add("a>b", "c")
+	f.Add("data-x", `"><script>alert(1)</script>`)
+	f.Add(" hidden ", "")
+
+	f.Fuzz(func(t *testing.T, key, value string) {
+		element := &Element{Tag: "div", Attrs: KV{key: value}}
+		var builder strings.Builder
+		if err := element.renderAttrs(&builder, newRenderer()); err != nil {
+			return
+		}
+
+		// whatever was accepted must parse back as exactly this attribute
+		tokenizer := html.NewTokenizer(strings.NewReader("<div" + builder.String() + ">"))
+		if tokenizer.Next() != html.StartTagToken {
+			t.Fatalf("renderAttrs(%q, %q) = %q, doesn't parse as a start tag", key, value, builder.String())
+		}
+		token := tokenizer.Token()
+		if token.Data != "div" || len(token.Attr) != 1 {
+			t.Fatalf("renderAttrs(%q, %q) = %q, parses as %v", key, value, builder.String(), token)
+		}
+		attr := token.Attr[0]
+		// the parser lowercases ASCII letters only
+		wantKey := strings.Map(func(r rune) rune {
+			if r >= 'A' && r <= 'Z' {
+				return r + 'a' - 'A'
+			}
+			return r
+		}, strings.TrimSpace(key))
+		if attr.Key != wantKey {
+			t.Fatalf("renderAttrs(%q, %q) = %q, parses with key %q", key, value, builder.String(), attr.Key)
+		}
+		// the parser normalizes newlines, which is not an injection
+		want := strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(value)
+		if !urlAttrs[attr.Key] && attr.Val != want {
+			t.Fatalf("renderAttrs(%q, %q) = %q, parses with value %q", key, value, builder.String(), attr.Val)
+		}
+	})
+}
+
+// attrValue is a test AttrValuer returning fixed results.
+type attrValue struct {
+	value string
+	err   error
+}
+
+func (me attrValue) AttrValue() (string, error) {
+	return me.value, me.err
+}
+
+func TestIsVoidTag(t *testing.T) {
+	for tag, expected := range map[string]bool{"br": true, "IMG": true, "div": false, "": false, "my-br": false} {
+		if result := IsVoidTag(tag); result != expected {
+			t.Errorf("IsVoidTag(%q) = %v, want %v", tag, result, expected)
+		}
+	}
+}
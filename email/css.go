@@ -0,0 +1,91 @@
+package email
+
+import (
+	"strings"
+
+	"github.com/assaidy/g/internal/cssscan"
+)
+
+// statement is a top-level CSS statement: a rule, or an at-rule kept as is.
+type statement struct {
+	prelude string // the selectors of a rule, or the whole at-rule
+	block   string // the declarations of a rule
+	atRule  bool
+}
+
+// parseSheet splits a style sheet into statements.
+func parseSheet(css string) []statement {
+	css = cssscan.StripComments(css)
+	var result []statement
+	for i := 0; i < len(css); {
+		for i < len(css) && cssscan.IsSpace(css[i]) {
+			i++
+		}
+		if i == len(css) {
+			break
+		}
+
+		end := cssscan.Scan(css, i, "{;")
+		if end == len(css) || css[end] == ';' {
+			// @import, @charset, or garbage
+			if s := strings.TrimSpace(css[i:min(end+1, len(css))]); strings.HasPrefix(s, "@") {
+				result = append(result, statement{prelude: s, atRule: true})
+			}
+			i = end + 1
+			continue
+		}
+		close := cssscan.BlockEnd(css, end)
+		prelude := strings.TrimSpace(css[i:end])
+		if strings.HasPrefix(prelude, "@") {
+			result = append(result, statement{prelude: strings.TrimSpace(css[i:min(close+1, len(css))]), atRule: true})
+		} else {
+			result = append(result, statement{prelude: prelude, block: css[end+1 : min(close, len(css))]})
+		}
+		i = close + 1
+	}
+	return result
+}
+
+// declaration is a CSS property declaration.
+type declaration struct {
+	property  string
+	value     string
+	important bool
+}
+
+func (me declaration) String() string {
+	if me.important {
+		return me.property + ": " + me.value + " !important"
+	}
+	return me.property + ": " + me.value
+}
+
+// parseDeclarations parses the declarations of a rule or of a style
+// attribute. Invalid declarations are dropped.
+func parseDeclarations(block string) []declaration {
+	var result []declaration
+	for i := 0; i < len(block); {
+		end := cssscan.Scan(block, i, ";")
+		property, value, ok := strings.Cut(block[i:end], ":")
+		i = end + 1
+		property = strings.ToLower(strings.TrimSpace(property))
+		value = strings.TrimSpace(value)
+		if !ok || property == "" || value == "" {
+			continue
+		}
+		d := declaration{property: property, value: value}
+		if j := strings.LastIndexByte(value, '!'); j >= 0 && strings.EqualFold(strings.TrimSpace(value[j+1:]), "important") {
+			d.value, d.important = strings.TrimSpace(value[:j]), true
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+func formatDeclarations(decls []declaration) string {
+	parts := make([]string, len(decls))
+	for i, d := range decls {
+		parts[i] = d.String()
+	}
+	return strings.Join(parts, "; ")
+}
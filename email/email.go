@@ -0,0 +1,359 @@
+// Package email renders g trees for HTML email, whose clients ignore or
+// strip <style> elements: the CSS rules of the page are inlined into style
+// attributes, and a plain-text alternative is rendered along.
+//
+// Example:
+//
+//	msg, err := email.Render(welcomeEmail(user))
+//	if err != nil {
+//		return err
+//	}
+//	for _, w := range msg.Warnings {
+//		log.Print(w)
+//	}
+//	// send msg.HTML and msg.Text as the parts of a multipart/alternative message
+package email
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/assaidy/g"
+	"github.com/assaidy/g/internal/cssscan"
+	"github.com/assaidy/g/selector"
+)
+
+// Message is an email body, ready for the text/html and text/plain parts of
+// a multipart/alternative message.
+type Message struct {
+	HTML     string
+	Text     string
+	Warnings []Warning
+}
+
+// Warning is something of the page that email clients won't render as in a
+// browser.
+//
+// Path is the list of child indexes of the element, counted like the Path of
+// a g.Patch.
+type Warning struct {
+	Path    []int
+	Message string
+}
+
+func (me Warning) String() string {
+	return fmt.Sprintf("at %v: %s", me.Path, me.Message)
+}
+
+// unsupported are the elements that email clients strip or don't run.
+var unsupported = map[string]string{
+	"audio":    "<audio> is not supported by most email clients",
+	"button":   "<button> is not supported by most email clients; use a styled link",
+	"canvas":   "<canvas> is not supported by email clients",
+	"embed":    "<embed> is not supported by email clients",
+	"form":     "<form> is not supported by most email clients",
+	"iframe":   "<iframe> is not supported by email clients",
+	"input":    "<input> is not supported by most email clients",
+	"noscript": "<noscript> is not supported by email clients",
+	"object":   "<object> is not supported by email clients",
+	"script":   "<script> is removed by email clients",
+	"select":   "<select> is not supported by most email clients",
+	"svg":      "<svg> is not supported by most email clients; use an image",
+	"textarea": "<textarea> is not supported by most email clients",
+	"video":    "<video> is not supported by most email clients",
+}
+
+// Render inlines the CSS of node and renders it as HTML and plain text.
+//
+// The rules of the Style elements, including those marked with g.HeadItem
+// such as the CSS of a css.Scope, are applied to the elements they match,
+// in cascade order: !important declarations win over the style attribute of
+// the element, which wins over the rules, and among rules the most specific
+// selector wins, then the last rule. The merged declarations replace the
+// style attribute. Media queries and other
+// at-rules, and the rules whose selectors can't be matched statically (such
+// as :hover), are kept in a single Style element in Head, or at the start of
+// the page when there is none.
+//
+// node isn't modified. Unsupported elements and external style sheets are
+// reported as warnings.
+func Render(node g.Node) (*Message, error) {
+	in := &inliner{}
+	roots, err := in.extract(nil, copyNodes([]g.Node{node}))
+	if err != nil {
+		return nil, err
+	}
+	in.parse()
+
+	root := &g.Element{Children: roots}
+	selector.Walk(root, func(e *g.Element, pos *selector.Position) {
+		in.apply(e, pos)
+	})
+	if len(in.residual) > 0 {
+		style := g.Style(cssscan.Raw(strings.Join(in.residual, "\n")))
+		if head := findHead(roots); head != nil {
+			head.Children = append(head.Children, style)
+		} else {
+			root.Children = append([]g.Node{style}, root.Children...)
+		}
+	}
+
+	var html, text strings.Builder
+	if err := g.Render(&html, root); err != nil {
+		return nil, err
+	}
+	if err := g.RenderText(&text, root); err != nil {
+		return nil, err
+	}
+	return &Message{HTML: html.String(), Text: text.String(), Warnings: in.warnings}, nil
+}
+
+type inliner struct {
+	sheets   []sheet
+	rules    []rule
+	residual []string
+	warnings []Warning
+}
+
+// sheet is the content of a Style element.
+type sheet struct {
+	path  []int
+	css   string
+	media string
+}
+
+// rule is a CSS rule that can be inlined.
+type rule struct {
+	selectors []*selector.Selector
+	decls     []declaration
+	order     int
+}
+
+func (me *inliner) warn(path []int, format string, args ...any) {
+	me.warnings = append(me.warnings, Warning{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// extract removes the Style elements of nodes, which are copies, keeping
+// their style sheets, and reports unsupported elements.
+func (me *inliner) extract(path []int, nodes []g.Node) ([]g.Node, error) {
+	var result []g.Node
+	for i, node := range nodes {
+		p := append(slices.Clip(path), i)
+		if item, ok := g.UnwrapHeadItem(node); ok {
+			// the CSS of components, such as css.Scope
+			if e, ok := item.(*g.Element); ok && strings.EqualFold(e.Tag, "style") {
+				if err := me.addSheet(p, e); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		e, ok := node.(*g.Element)
+		if !ok {
+			result = append(result, node)
+			continue
+		}
+		tag := strings.ToLower(e.Tag)
+		switch tag {
+		case "style":
+			if err := me.addSheet(p, e); err != nil {
+				return nil, err
+			}
+			continue
+		case "link":
+			if rel, _ := selector.Attr(e, "rel"); slices.Contains(strings.Fields(strings.ToLower(rel)), "stylesheet") {
+				me.warn(p, "external style sheets are not inlined and most email clients don't load them")
+			}
+		case "template":
+			result = append(result, e)
+			continue
+		}
+		if message, ok := unsupported[tag]; ok {
+			me.warn(p, "%s", message)
+		}
+		children, err := me.extract(p, e.Children)
+		if err != nil {
+			return nil, err
+		}
+		e.Children = children
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+// addSheet keeps the style sheet of the Style element e, once.
+func (me *inliner) addSheet(path []int, e *g.Element) error {
+	css, err := styleCSS(e)
+	if err != nil {
+		return err
+	}
+	media, _ := selector.Attr(e, "media")
+	for _, s := range me.sheets {
+		if s.css == css && s.media == media {
+			return nil
+		}
+	}
+	me.sheets = append(me.sheets, sheet{path: path, css: css, media: media})
+	return nil
+}
+
+// parse splits the style sheets into the rules to inline and the residual
+// CSS.
+func (me *inliner) parse() {
+	for _, s := range me.sheets {
+		if s.media != "" && !strings.EqualFold(s.media, "all") {
+			me.residual = append(me.residual, "@media "+s.media+" {\n"+strings.TrimSpace(s.css)+"\n}")
+			continue
+		}
+		for _, st := range parseSheet(s.css) {
+			if st.atRule {
+				me.residual = append(me.residual, st.prelude)
+				continue
+			}
+			r := rule{decls: parseDeclarations(st.block), order: len(me.rules)}
+			var kept []string
+			for i := 0; i < len(st.prelude); {
+				end := cssscan.Scan(st.prelude, i, ",")
+				src := strings.TrimSpace(st.prelude[i:end])
+				i = end + 1
+				sels, err := selector.Parse(src)
+				if err != nil {
+					kept = append(kept, src)
+					continue
+				}
+				r.selectors = append(r.selectors, sels...)
+			}
+			if len(kept) > 0 {
+				me.warn(s.path, "%s can't be inlined and is kept in <style>", strings.Join(kept, ", "))
+				me.residual = append(me.residual, strings.Join(kept, ", ")+" { "+formatDeclarations(r.decls)+" }")
+			}
+			if len(r.selectors) > 0 {
+				me.rules = append(me.rules, r)
+			}
+		}
+	}
+}
+
+// ranked is a declaration applying to an element, with its cascade rank.
+type ranked struct {
+	declaration
+	inline      bool
+	specificity [3]int
+	order       int
+}
+
+func (me ranked) less(other ranked) bool {
+	switch {
+	case me.important != other.important:
+		return other.important
+	case me.inline != other.inline:
+		return other.inline
+	}
+	if c := selector.CompareSpecificity(me.specificity, other.specificity); c != 0 {
+		return c < 0
+	}
+	return me.order < other.order
+}
+
+// apply sets the style attribute of e to the declarations of the rules
+// matching it and of its own style attribute, in cascade order.
+func (me *inliner) apply(e *g.Element, pos *selector.Position) {
+	var decls []ranked
+	for _, r := range me.rules {
+		// a rule applies once, with its most specific matching selector
+		matched, best := false, [3]int{}
+		for _, sel := range r.selectors {
+			if spec := sel.Specificity(); sel.Matches(pos) && (!matched || selector.CompareSpecificity(spec, best) > 0) {
+				matched, best = true, spec
+			}
+		}
+		if !matched {
+			continue
+		}
+		for _, d := range r.decls {
+			decls = append(decls, ranked{declaration: d, specificity: best, order: r.order})
+		}
+	}
+	if len(decls) == 0 {
+		return
+	}
+	if style, ok := selector.Attr(e, "style"); ok {
+		for _, d := range parseDeclarations(style) {
+			decls = append(decls, ranked{declaration: d, inline: true})
+		}
+	}
+
+	slices.SortStableFunc(decls, func(a, b ranked) int {
+		switch {
+		case a.less(b):
+			return -1
+		case b.less(a):
+			return 1
+		}
+		return 0
+	})
+	last := map[string]int{}
+	for i, d := range decls {
+		last[d.property] = i
+	}
+	var winners []declaration
+	for i, d := range decls {
+		if last[d.property] == i {
+			winners = append(winners, d.declaration)
+		}
+	}
+	e.Attrs["style"] = formatDeclarations(winners)
+}
+
+func findHead(nodes []g.Node) *g.Element {
+	for _, node := range nodes {
+		e, ok := node.(*g.Element)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(e.Tag) {
+		case "head":
+			return e
+		case "html":
+			return findHead(e.Children)
+		}
+	}
+	return nil
+}
+
+// copyNodes returns copies of nodes, flattened with g.Flatten so that
+// paths count like in a g.Patch.
+func copyNodes(nodes []g.Node) []g.Node {
+	result := g.Flatten(nodes)
+	for i, node := range result {
+		if e, ok := node.(*g.Element); ok {
+			attrs := g.KV{}
+			maps.Copy(attrs, e.Attrs)
+			result[i] = &g.Element{Tag: e.Tag, IsVoid: e.IsVoid, Attrs: attrs, Children: copyNodes(e.Children)}
+		}
+	}
+	return result
+}
+
+// styleCSS returns the CSS of the Style element e. Text children are read
+// as is, since the CSS of a g.Text isn't meant to be escaped, and other
+// children, such as a css.Stylesheet, are rendered.
+func styleCSS(e *g.Element) (string, error) {
+	var sb strings.Builder
+	for _, child := range e.Children {
+		switch n := child.(type) {
+		case nil:
+		case g.Text:
+			sb.WriteString(string(n))
+		default:
+			s, err := n.Render()
+			if err != nil {
+				return "", fmt.Errorf("couldn't read <style>: %w", err)
+			}
+			sb.WriteString(s)
+		}
+	}
+	return sb.String(), nil
+}
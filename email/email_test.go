@@ -0,0 +1,160 @@
+package email
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/assaidy/g"
+	"github.com/assaidy/g/css"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     g.Node
+		expected string
+	}{
+		{
+			name: "Rules are inlined",
+			node: g.Div(
+				g.Style(g.Text("p { color: red; margin: 0 } .note { color: blue }")),
+				g.P(g.Text("a")),
+				g.P(g.KV{"class": "note"}, g.Text("b")),
+			),
+			expected: `<div><p style="color: red; margin: 0">a</p><p class="note" style="margin: 0; color: blue">b</p></div>`,
+		},
+		{
+			name: "Stylesheet",
+			node: g.Div(
+				g.Style(css.Stylesheet{css.Rule("div > p", css.Props{css.Color("red")})}),
+				g.P(g.Text("a")),
+			),
+			expected: `<div><p style="color: red">a</p></div>`,
+		},
+		{
+			name: "Specificity and order",
+			node: g.Empty(
+				g.Style(g.Text("#x { color: green } p.a { color: blue } p { color: red } div > p { color: black } p { color: gray }")),
+				g.Div(g.P(g.KV{"id": "x", "class": "a"}, g.Text("a")), g.P(g.KV{"class": "a"}, g.Text("b")), g.P(g.Text("c"))),
+			),
+			expected: `<div><p class="a" id="x" style="color: green">a</p><p class="a" style="color: blue">b</p><p style="color: black">c</p></div>`,
+		},
+		{
+			name: "Style attribute and !important",
+			node: g.Empty(
+				g.Style(g.Text("p { color: red !important; margin: 0; padding: 1px }")),
+				g.P(g.KV{"style": "color: blue; margin-top: 4px; padding: 0 !important"}, g.Text("a")),
+			),
+			expected: `<p style="margin: 0; margin-top: 4px; color: red !important; padding: 0 !important">a</p>`,
+		},
+		{
+			name: "Media queries and pseudo-classes are kept in head",
+			node: g.Html(
+				g.Head(g.Style(g.Text("a { color: red } a:hover { color: blue } @media (max-width: 600px) { div > a { display: block } }"))),
+				g.Body(g.A(g.KV{"href": "/x"}, g.Text("x"))),
+			),
+			expected: `<html><head><style>a:hover { color: blue }` + "\n" + `@media (max-width: 600px) { div > a { display: block } }</style></head>` +
+				`<body><a href="/x" style="color: red">x</a></body></html>`,
+		},
+		{
+			name: "Style element with media",
+			node: g.Empty(
+				g.Style(g.KV{"media": "print"}, g.Text("p { color: black }")),
+				g.P(g.Text("a")),
+			),
+			expected: "<style>@media print {\np { color: black }\n}</style><p>a</p>",
+		},
+		{
+			name: "Comments and strings",
+			node: g.Empty(
+				g.Style(g.Text(`/* p { color: red } */ p::after { content: "}" } p[title="a,b"] { font-family: "A; B", serif }`)),
+				g.P(g.KV{"title": "a,b"}, g.Text("a")),
+			),
+			expected: `<style>p::after { content: "}" }</style><p style="font-family: &#34;A; B&#34;, serif" title="a,b">a</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := Render(tt.node)
+			if err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if msg.HTML != tt.expected {
+				t.Errorf("Render().HTML = %q, want %q", msg.HTML, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRender_Text(t *testing.T) {
+	msg, err := Render(g.Html(
+		g.Head(g.Style(g.Text("h1 { color: red }"))),
+		g.Body(g.H1(g.Text("Welcome")), g.P(g.Text("Confirm your "), g.A(g.KV{"href": "https://example.com/c"}, g.Text("account")), g.Text("."))),
+	))
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	want := "Welcome\n=======\n\nConfirm your account (https://example.com/c).\n"
+	if msg.Text != want {
+		t.Errorf("Render().Text = %q, want %q", msg.Text, want)
+	}
+}
+
+func TestRender_Warnings(t *testing.T) {
+	msg, err := Render(g.Html(
+		g.Head(g.Link(g.KV{"rel": "stylesheet", "href": "/app.css"}), g.Style(g.Text("a:focus { outline: 0 }"))),
+		g.Body(g.Empty(g.Text("a"), g.Text("b")), g.Form(g.Input(g.KV{"name": "q"})), g.Script(g.Text("x()"))),
+	))
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+
+	var got []string
+	for _, w := range msg.Warnings {
+		got = append(got, w.String())
+	}
+	want := []string{
+		"at [0 0 0]: external style sheets are not inlined and most email clients don't load them",
+		"at [0 1 1]: <form> is not supported by most email clients",
+		"at [0 1 1 0]: <input> is not supported by most email clients",
+		"at [0 1 2]: <script> is removed by email clients",
+		"at [0 0 1]: a:focus can't be inlined and is kept in <style>",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Render().Warnings =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRender_ScopedCSS(t *testing.T) {
+	card := css.Scoped("& { padding: 0 } p { margin: 0 } &:hover { color: red }")
+	msg, err := Render(g.Html(g.Head(), g.Body(card.Apply(g.Div(g.P(g.Text("a")))), card.Apply(g.Div()))))
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	want := `<html><head><style>.` + card.Class + `:hover { color: red }</style></head><body>` +
+		`<div class="` + card.Class + `" style="padding: 0"><p style="margin: 0">a</p></div>` +
+		`<div class="` + card.Class + `" style="padding: 0"></div></body></html>`
+	if msg.HTML != want {
+		t.Errorf("Render().HTML = %q, want %q", msg.HTML, want)
+	}
+}
+
+func TestRender_StyleError(t *testing.T) {
+	_, err := Render(g.Div(g.Style(css.Stylesheet{css.Props{css.Color("red")}}), g.P(g.Text("a"))))
+	if err == nil {
+		t.Fatal("Render() should fail on a Style that fails to render")
+	}
+}
+
+func TestRender_DoesNotModifyNode(t *testing.T) {
+	p := g.P(g.KV{"style": "margin: 0"}, g.Text("a"))
+	page := g.Div(g.Style(g.Text("p { color: red }")), p)
+	if _, err := Render(page); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	if len(page.Children) != 2 || p.Attrs["style"] != "margin: 0" {
+		t.Errorf("Render() modified the node: %v", page.Children)
+	}
+}
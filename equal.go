@@ -0,0 +1,144 @@
+package g
+
+import (
+	"maps"
+	"strings"
+	"unicode"
+)
+
+// Normalize returns a copy of node in canonical form, which renders
+// equivalent HTML:
+//   - tagless elements are replaced by their children,
+//   - adjacent Text nodes are merged and whitespace runs are collapsed the
+//     way Text renders them, and empty Text nodes are removed,
+//   - false boolean attributes and the children of void elements, which are
+//     never rendered, are removed.
+//
+// When node flattens to more or less than one node, the result is a tagless
+// element holding them. Nodes other than Text and *Element are kept as is.
+//
+// Example:
+//
+//	Normalize(Div(Empty(Text("a  "), Text(" b")), Empty()))
+//	// Div(Text("a b"))
+func Normalize(node Node) Node {
+	nodes := normalizeChildren([]Node{node})
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+	return &Element{Children: nodes}
+}
+
+func normalizeChildren(nodes []Node) []Node {
+	var result []Node
+	for _, node := range Flatten(nodes) {
+		switch n := node.(type) {
+		case Text:
+			if s := CollapseSpace(string(n)); s != "" {
+				result = append(result, Text(s))
+			}
+		case *Element:
+			e := &Element{Tag: n.Tag, IsVoid: n.IsVoid}
+			for k, v := range n.Attrs {
+				if b, ok := v.(bool); ok && !b {
+					continue
+				}
+				if e.Attrs == nil {
+					e.Attrs = make(KV, len(n.Attrs))
+				}
+				e.Attrs[k] = v
+			}
+			if !n.IsVoid {
+				e.Children = normalizeChildren(n.Children)
+			}
+			result = append(result, e)
+		default:
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// CollapseSpace collapses whitespace runs to a single space, like
+// Text.Render.
+//
+// Example:
+//
+//	CollapseSpace(" a \n\t b ") // " a b "
+func CollapseSpace(s string) string {
+	if s == "" {
+		return ""
+	}
+	var sb strings.Builder
+	space := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space {
+			sb.WriteByte(' ')
+			space = false
+		}
+		sb.WriteRune(r)
+	}
+	if space {
+		sb.WriteByte(' ')
+	}
+	return sb.String()
+}
+
+// Equal reports whether a and b render equivalent HTML, ignoring tagless
+// wrappers, how text is split into Text nodes, whitespace runs and the
+// order in which attributes were given. Attribute values are compared as
+// rendered, so a UniqueID equals the literal id it renders to. A node that
+// fails to render, or holds an attribute that does, equals no other node.
+//
+// Example:
+//
+//	Equal(P(Text("a"), Text(" b")), Empty(P(Text("a b")))) // true
+func Equal(a, b Node) bool {
+	ra, rb := newRenderer(InDocument(a)), newRenderer(InDocument(b))
+	return equalNodes(normalizeChildren([]Node{a}), normalizeChildren([]Node{b}), ra, rb)
+}
+
+func equalNodes(a, b []Node, ra, rb *renderer) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !equalNode(a[i], b[i], ra, rb) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalNode(a, b Node, ra, rb *renderer) bool {
+	switch x := a.(type) {
+	case Text:
+		y, ok := b.(Text)
+		return ok && x == y
+	case *Element:
+		y, ok := b.(*Element)
+		if !ok || !strings.EqualFold(x.Tag, y.Tag) || x.IsVoid != y.IsVoid {
+			return false
+		}
+		attrsA, errA := renderedAttrs(x.Attrs, ra)
+		attrsB, errB := renderedAttrs(y.Attrs, rb)
+		if errA != nil || errB != nil || !maps.Equal(attrsA, attrsB) {
+			return false
+		}
+		return equalNodes(x.Children, y.Children, ra, rb)
+	default:
+		if _, isText := b.(Text); isText {
+			return false
+		}
+		if _, isElement := b.(*Element); isElement {
+			return false
+		}
+		sa, errA := a.Render()
+		sb, errB := b.Render()
+		return errA == nil && errB == nil && sa == sb
+	}
+}
@@ -0,0 +1,138 @@
+package g
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// describe returns the structure of node: Text nodes quoted, elements as
+// tag[attrs](children), void elements as tag[attrs]/.
+func describe(node Node) string {
+	switch n := node.(type) {
+	case Text:
+		return fmt.Sprintf("%q", string(n))
+	case *Element:
+		var sb strings.Builder
+		sb.WriteString(n.Tag)
+		if len(n.Attrs) > 0 {
+			var attrs []string
+			for _, k := range slices.Sorted(maps.Keys(n.Attrs)) {
+				attrs = append(attrs, fmt.Sprintf("%s=%v", k, n.Attrs[k]))
+			}
+			sb.WriteString("[" + strings.Join(attrs, " ") + "]")
+		}
+		if n.IsVoid {
+			return sb.String() + "/"
+		}
+		var children []string
+		for _, child := range n.Children {
+			children = append(children, describe(child))
+		}
+		sb.WriteString("(" + strings.Join(children, " ") + ")")
+		return sb.String()
+	default:
+		return fmt.Sprintf("%T", n)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     Node
+		expected string
+	}{
+		{
+			name:     "Tagless containers and adjacent text",
+			node:     Div(Empty(Text("a  "), Text(" b")), Empty(), Text("\n")),
+			expected: `div("a b ")`,
+		},
+		{
+			name:     "False attributes and void children",
+			node:     &Element{Tag: "br", IsVoid: true, Attrs: KV{"hidden": false}, Children: []Node{Text("x")}},
+			expected: `br/`,
+		},
+		{
+			name:     "Tagless root with several children",
+			node:     Empty(P(), Empty(Text("")), Text("x")),
+			expected: `(p() "x")`,
+		},
+		{
+			name:     "Single text",
+			node:     Empty(Text(" a"), Text("b ")),
+			expected: `" ab "`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Normalize(tt.node)
+			got := describe(result)
+			if got != tt.expected {
+				t.Errorf("Normalize() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalize_DoesNotModify(t *testing.T) {
+	node := Div(Empty(Text("a")), Br(KV{"x": false}))
+	before := describe(node)
+	Normalize(node)
+	if after := describe(node); after != before {
+		t.Errorf("Normalize() modified its argument: %s, was %s", after, before)
+	}
+}
+
+func TestCollapseSpace(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "", expected: ""},
+		{input: "a", expected: "a"},
+		{input: "  a \n\t b  ", expected: " a b "},
+		{input: "\n", expected: " "},
+	}
+
+	for _, tt := range tests {
+		if result := CollapseSpace(tt.input); result != tt.expected {
+			t.Errorf("CollapseSpace(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	id := UniqueID("x")
+	tests := []struct {
+		name     string
+		a, b     Node
+		expected bool
+	}{
+		{name: "Same tree", a: Div(P(Text("a"))), b: Div(P(Text("a"))), expected: true},
+		{name: "Tagless wrappers", a: Empty(Div(Empty(P()))), b: Div(P()), expected: true},
+		{name: "Split text and whitespace", a: P(Text("a "), Text("\n b")), b: P(Text("a b")), expected: true},
+		{name: "Merged attributes", a: Div(KV{"a": "1"}, KV{"b": "2"}), b: Div(KV{"b": "2", "a": "1"}), expected: true},
+		{name: "False attribute", a: Input(KV{"disabled": false}), b: Input(), expected: true},
+		{name: "UniqueID and literal id", a: P(KV{"id": id}), b: P(KV{"id": "x-1"}), expected: true},
+		{name: "Different text", a: P(Text("a")), b: P(Text("b")), expected: false},
+		{name: "Different tag", a: P(), b: Div(), expected: false},
+		{name: "Different attribute", a: P(KV{"class": "a"}), b: P(KV{"class": "b"}), expected: false},
+		{name: "Extra child", a: Ul(Li()), b: Ul(Li(), Li()), expected: false},
+		{name: "Text and element", a: Div(Text("a")), b: Div(Span(Text("a"))), expected: false},
+		{name: "Attributes failing to render", a: P(KV{"title": attrValue{err: errors.New("a")}}), b: P(KV{"title": attrValue{err: errors.New("b")}}), expected: false},
+		{name: "Attribute failing to render and missing", a: P(KV{"title": attrValue{err: errors.New("a")}}), b: P(), expected: false},
+		{name: "Invalid attribute type", a: P(KV{"title": 1}), b: P(KV{"title": 2}), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Equal(tt.a, tt.b); result != tt.expected {
+				t.Errorf("Equal() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/assaidy/g"
+	"github.com/assaidy/g/css"
 )
 
 func main() {
@@ -43,7 +44,7 @@ func loginPage() g.Node {
 	return pageLayout("login", g.Empty(
 		loginPageStyle(),
 
-		g.Form(g.KV{"method": "post"},
+		loginFormStyle.Apply(g.Form(g.KV{"method": "post"},
 			g.H1(g.Text("Login")),
 			g.Div(
 				g.Label(g.Text("Username:")),
@@ -56,7 +57,7 @@ func loginPage() g.Node {
 			g.Div(
 				g.Button(g.KV{"type": "submit"}, g.Text("Login")),
 			),
-		),
+		)),
 	))
 }
 
@@ -72,50 +73,53 @@ func loginPageStyle() g.Node {
 				align-items: center;
 				min-height: 100vh;
 			}
-			form {
-				background: white;
-				padding: 2rem;
-				border-radius: 10px;
-				box-shadow: 0 10px 25px rgba(0,0,0,0.2);
-				width: 100%;
-				max-width: 400px;
-			}
-			div {
-				margin-bottom: 1rem;
-			}
-			label {
-				display: block;
-				margin-bottom: 0.5rem;
-				font-weight: bold;
-				color: #333;
-			}
-			input {
-				width: 100%;
-				padding: 0.75rem;
-				border: 2px solid #ddd;
-				border-radius: 5px;
-				font-size: 1rem;
-				box-sizing: border-box;
-				transition: border-color 0.3s;
-			}
-			input:focus {
-				outline: none;
-				border-color: #667eea;
-			}
-			button {
-				width: 100%;
-				padding: 0.75rem;
-				background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
-				color: white;
-				border: none;
-				border-radius: 5px;
-				font-size: 1rem;
-				font-weight: bold;
-				cursor: pointer;
-				transition: transform 0.2s;
-			}
-			button:hover {
-				transform: translateY(-2px);
-			}
 	`))
 }
+
+var loginFormStyle = css.Scoped(`
+	& {
+		background: white;
+		padding: 2rem;
+		border-radius: 10px;
+		box-shadow: 0 10px 25px rgba(0,0,0,0.2);
+		width: 100%;
+		max-width: 400px;
+	}
+	div {
+		margin-bottom: 1rem;
+	}
+	label {
+		display: block;
+		margin-bottom: 0.5rem;
+		font-weight: bold;
+		color: #333;
+	}
+	input {
+		width: 100%;
+		padding: 0.75rem;
+		border: 2px solid #ddd;
+		border-radius: 5px;
+		font-size: 1rem;
+		box-sizing: border-box;
+		transition: border-color 0.3s;
+	}
+	input:focus {
+		outline: none;
+		border-color: #667eea;
+	}
+	button {
+		width: 100%;
+		padding: 0.75rem;
+		background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+		color: white;
+		border: none;
+		border-radius: 5px;
+		font-size: 1rem;
+		font-weight: bold;
+		cursor: pointer;
+		transition: transform 0.2s;
+	}
+	button:hover {
+		transform: translateY(-2px);
+	}
+`)
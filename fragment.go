@@ -0,0 +1,81 @@
+package g
+
+import (
+	"fmt"
+	"io"
+)
+
+// fragmentKey is the attribute holding the name of a fragment marker. It is
+// never rendered, since fragments are tagless elements.
+const fragmentKey = "g:fragment"
+
+// Fragment marks node as a named part of a page that can be rendered on its
+// own with RenderFragment.
+//
+// The marker is a tagless element, so it doesn't change the rendered page.
+// A nil node makes an empty fragment.
+//
+// Example:
+//
+//	page := Html(Body(
+//		H1(Text("Todos")),
+//		Fragment("list", Ul(Li(Text("Buy milk")))),
+//	))
+//	err := RenderFragment(w, page, "list")
+//	// Outputs: <ul><li>Buy milk</li></ul>
+func Fragment(name string, node Node) *Element {
+	e := &Element{Attrs: KV{fragmentKey: name}}
+	if node != nil {
+		e.Children = []Node{node}
+	}
+	return e
+}
+
+// RenderFragment writes only the fragment with the given name, found
+// anywhere in page, to the provided io.Writer.
+//
+// It returns an error when no fragment or more than one fragment has that
+// name.
+func RenderFragment(writer io.Writer, page Node, name string) error {
+	var found []*Element
+	findFragments(page, name, &found)
+
+	switch len(found) {
+	case 0:
+		return fmt.Errorf("fragment '%s' not found", name)
+	case 1:
+		return Render(writer, found[0], InDocument(page))
+	default:
+		return fmt.Errorf("fragment '%s' is defined %d times", name, len(found))
+	}
+}
+
+func findFragments(node Node, name string, found *[]*Element) {
+	e, ok := node.(*Element)
+	if !ok {
+		return
+	}
+	if v, ok := e.Attrs[fragmentKey].(string); ok && e.Tag == "" && v == name {
+		*found = append(*found, e)
+	}
+	for _, child := range e.Children {
+		findFragments(child, name, found)
+	}
+}
+
+// FindByID returns the first element of page whose id is id, as rendered, or
+// nil. UniqueIDs are resolved in page.
+//
+// Example:
+//
+//	err := Render(w, FindByID(page, "list"), InDocument(page))
+func FindByID(page Node, id string) *Element {
+	r := newRenderer(InDocument(page))
+	var found *Element
+	walkElements(page, func(e *Element) {
+		if v, ok := r.renderedID(e); ok && found == nil && v == id {
+			found = e
+		}
+	})
+	return found
+}
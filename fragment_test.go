@@ -0,0 +1,116 @@
+package g
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRenderFragment(t *testing.T) {
+	page := Html(
+		Body(
+			H1(Text("Todos")),
+			Fragment("list", Ul(Li(Text("one")), Fragment("item", Li(Text("two"))))),
+			Fragment("dup", P()),
+			Div(Fragment("dup", Span())),
+			Fragment("empty", nil),
+		),
+	)
+
+	tests := []struct {
+		name     string
+		fragment string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Top level fragment",
+			fragment: "list",
+			expected: "<ul><li>one</li><li>two</li></ul>",
+			wantErr:  false,
+		},
+		{
+			name:     "Nested fragment",
+			fragment: "item",
+			expected: "<li>two</li>",
+			wantErr:  false,
+		},
+		{
+			name:     "Missing fragment",
+			fragment: "missing",
+			wantErr:  true,
+		},
+		{
+			name:     "Nil fragment",
+			fragment: "empty",
+			expected: "",
+			wantErr:  false,
+		},
+		{
+			name:     "Duplicated fragment",
+			fragment: "dup",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := RenderFragment(&buf, page, tt.fragment)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RenderFragment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && buf.String() != tt.expected {
+				t.Errorf("RenderFragment() = %q, want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestFragment_RendersInPlace(t *testing.T) {
+	result, err := Div(Fragment("x", P(Text("a")))).Render()
+	if err != nil {
+		t.Errorf("Element.Render() error: %v", err)
+		return
+	}
+	if result != "<div><p>a</p></div>" {
+		t.Errorf("Element.Render() = %q, want %q", result, "<div><p>a</p></div>")
+	}
+}
+
+func TestFindByID(t *testing.T) {
+	page := Body(
+		P(KV{"id": UniqueID("item")}, Text("first")),
+		Div(P(KV{"id": UniqueID("item")}, Text("second"))),
+		P(KV{"id": attrValue{value: "computed"}}, Text("third")),
+	)
+
+	tests := []struct {
+		name     string
+		id       string
+		expected string
+	}{
+		{name: "Generated id", id: "item-2", expected: `<p id="item-2">second</p>`},
+		{name: "AttrValuer id", id: "computed", expected: `<p id="computed">third</p>`},
+		{name: "Missing id", id: "item-3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := FindByID(page, tt.id)
+			if found == nil {
+				if tt.expected != "" {
+					t.Fatalf("FindByID() = nil, want %s", tt.expected)
+				}
+				return
+			}
+			var buf bytes.Buffer
+			if err := Render(&buf, found, InDocument(page)); err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("FindByID() = %q, want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
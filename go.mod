@@ -1,3 +1,9 @@
 module github.com/assaidy/g
 
 go 1.25.5
+
+require (
+	github.com/google/go-cmp v0.7.0
+	github.com/yuin/goldmark v1.8.2
+	golang.org/x/net v0.47.0
+)
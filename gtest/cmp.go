@@ -0,0 +1,18 @@
+package gtest
+
+import (
+	"github.com/assaidy/g"
+	"github.com/google/go-cmp/cmp"
+)
+
+// EquateNodes returns a cmp.Option that compares g.Node values with
+// g.Equal, so trees that render equivalent HTML are equal.
+//
+// Example:
+//
+//	if diff := cmp.Diff(want, got, gtest.EquateNodes()); diff != "" {
+//		t.Errorf("Card() mismatch (-want +got):\n%s", diff)
+//	}
+func EquateNodes() cmp.Option {
+	return cmp.Comparer(g.Equal)
+}
@@ -0,0 +1,26 @@
+package gtest
+
+import (
+	"testing"
+
+	"github.com/assaidy/g"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEquateNodes(t *testing.T) {
+	type card struct {
+		Title string
+		Body  g.Node
+	}
+
+	a := card{Title: "x", Body: g.Div(g.Empty(g.Text("a "), g.Text(" b")))}
+	b := card{Title: "x", Body: g.Div(g.Text("a b"))}
+	if diff := cmp.Diff(a, b, EquateNodes()); diff != "" {
+		t.Errorf("cmp.Diff() = %s, want no difference", diff)
+	}
+
+	c := card{Title: "x", Body: g.Div(g.Text("a c"))}
+	if cmp.Equal(a, c, EquateNodes()) {
+		t.Error("cmp.Equal() = true for trees with different text")
+	}
+}
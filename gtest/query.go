@@ -0,0 +1,134 @@
+package gtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/assaidy/g"
+	"github.com/assaidy/g/selector"
+)
+
+// HasText checks that the text of node contains text. Whitespace runs are
+// collapsed to a single space in both, and the content of script, style and
+// template elements is ignored.
+//
+// Example:
+//
+//	gtest.HasText(t, LoginForm(), "Log in")
+func HasText(t testing.TB, node g.Node, text string) {
+	t.Helper()
+	if !strings.Contains(collapse(textOf(node)), collapse(text)) {
+		t.Errorf("gtest.HasText: text %q not found in:\n%s", text, subtree(node))
+	}
+}
+
+// Count checks that want elements of node match the CSS selector.
+//
+// Example:
+//
+//	gtest.Count(t, TodoList(todos), "li", 4)
+func Count(t testing.TB, node g.Node, sel string, want int) {
+	t.Helper()
+	found, err := selector.Select(node, sel)
+	if err != nil {
+		t.Fatalf("gtest.Count: %v", err)
+	}
+	if len(found) != want {
+		t.Errorf("gtest.Count: %d elements match %q, want %d, in:\n%s", len(found), sel, want, subtree(node))
+	}
+}
+
+// Find returns the elements of node matching the CSS selector, for further
+// assertions. The test fails and stops when nothing matches.
+//
+// Example:
+//
+//	gtest.Find(t, LoginForm(), "input[name=password]").HasAttr("required")
+func Find(t testing.TB, node g.Node, sel string) *Selection {
+	t.Helper()
+	found, err := selector.Select(node, sel)
+	if err != nil {
+		t.Fatalf("gtest.Find: %v", err)
+	}
+	if len(found) == 0 {
+		t.Fatalf("gtest.Find: no element matches %q in:\n%s", sel, subtree(node))
+	}
+	return &Selection{t: t, selector: sel, Elements: found}
+}
+
+// Selection holds the elements found by Find. Its assertions check every
+// element and return the selection, so they can be chained.
+type Selection struct {
+	t        testing.TB
+	selector string
+	Elements []*g.Element
+}
+
+// HasAttr checks that the elements have the attribute key. Boolean
+// attributes set to false don't count.
+func (me *Selection) HasAttr(key string) *Selection {
+	me.t.Helper()
+	for _, e := range me.Elements {
+		if _, ok := selector.Attr(e, key); !ok {
+			me.t.Errorf("gtest: element matching %q has no %s attribute:\n%s", me.selector, key, subtree(e))
+		}
+	}
+	return me
+}
+
+// HasAttrValue checks that the attribute key of the elements is value.
+func (me *Selection) HasAttrValue(key, value string) *Selection {
+	me.t.Helper()
+	for _, e := range me.Elements {
+		if v, ok := selector.Attr(e, key); !ok || v != value {
+			me.t.Errorf("gtest: element matching %q doesn't have %s=%q:\n%s", me.selector, key, value, subtree(e))
+		}
+	}
+	return me
+}
+
+// HasText checks that the text of the elements contains text, like the
+// HasText function.
+func (me *Selection) HasText(text string) *Selection {
+	me.t.Helper()
+	for _, e := range me.Elements {
+		if !strings.Contains(collapse(textOf(e)), collapse(text)) {
+			me.t.Errorf("gtest: element matching %q doesn't contain the text %q:\n%s", me.selector, text, subtree(e))
+		}
+	}
+	return me
+}
+
+// textOf returns the text nodes of node, concatenated.
+func textOf(node g.Node) string {
+	switch n := node.(type) {
+	case g.Text:
+		return string(n)
+	case *g.Element:
+		switch strings.ToLower(n.Tag) {
+		case "script", "style", "template":
+			return ""
+		}
+		var sb strings.Builder
+		for _, child := range n.Children {
+			sb.WriteString(textOf(child))
+		}
+		return sb.String()
+	default:
+		return ""
+	}
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// subtree returns node in the pretty format, indented, for failure
+// messages.
+func subtree(node g.Node) string {
+	s, err := pretty(node)
+	if err != nil {
+		return "\t(render error: " + err.Error() + ")"
+	}
+	return "\t" + strings.ReplaceAll(strings.TrimSuffix(s, "\n"), "\n", "\n\t")
+}
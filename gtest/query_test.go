@@ -0,0 +1,74 @@
+package gtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+func loginForm() g.Node {
+	return g.Form(g.KV{"method": "post"},
+		g.H1(g.Text("Log   in")),
+		g.Input(g.KV{"name": "email", "type": "email", "required": true}),
+		g.Input(g.KV{"name": "password", "type": "password", "required": true}),
+		g.Button(g.KV{"type": "submit"}, g.Text("Log in"), g.Script(g.Text("secret"))),
+	)
+}
+
+func TestQueries(t *testing.T) {
+	HasText(t, loginForm(), "Log in")
+	Count(t, loginForm(), "input", 2)
+	Find(t, loginForm(), "input[name=password]").HasAttr("required").HasAttrValue("type", "password")
+	Find(t, loginForm(), "button").HasText("Log in")
+}
+
+func TestQueries_Failures(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    func(testing.TB)
+		expected []string // a substring of each failure
+	}{
+		{
+			name:     "Missing text",
+			check:    func(tb testing.TB) { HasText(tb, loginForm(), "secret") },
+			expected: []string{"text \"secret\" not found in:\n\t<form method=\"post\">"},
+		},
+		{
+			name:     "Wrong count",
+			check:    func(tb testing.TB) { Count(tb, loginForm(), "input", 3) },
+			expected: []string{`2 elements match "input", want 3`},
+		},
+		{
+			name:     "Nothing found",
+			check:    func(tb testing.TB) { Find(tb, loginForm(), "textarea").HasAttr("required") },
+			expected: []string{`no element matches "textarea"`},
+		},
+		{
+			name:     "Invalid selector",
+			check:    func(tb testing.TB) { Find(tb, loginForm(), "input[") },
+			expected: []string{`selector "input["`},
+		},
+		{
+			name:  "Missing attribute shows the element",
+			check: func(tb testing.TB) { Find(tb, loginForm(), "input").HasAttrValue("type", "email") },
+			expected: []string{
+				"element matching \"input\" doesn't have type=\"email\":\n\t<input name=\"password\" required type=\"password\">",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := record(t, t.Name(), tt.check)
+			if len(failures) != len(tt.expected) {
+				t.Fatalf("failures = %q, want %d", failures, len(tt.expected))
+			}
+			for i, want := range tt.expected {
+				if !strings.Contains(failures[i], want) {
+					t.Errorf("failure = %q, want it to contain %q", failures[i], want)
+				}
+			}
+		})
+	}
+}
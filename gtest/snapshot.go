@@ -0,0 +1,249 @@
+// Package gtest provides test helpers for g trees.
+//
+// Snapshot compares a tree with a golden file:
+//
+//	func TestCard(t *testing.T) {
+//		gtest.Snapshot(t, Card("Title", "Body"))
+//	}
+//
+// Run the tests with -update, or with GTEST_UPDATE=1 in the environment
+// when some packages don't use gtest, to write or rewrite the golden files:
+//
+//	go test . -update
+//	GTEST_UPDATE=1 go test ./...
+package gtest
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/assaidy/g"
+	nethtml "golang.org/x/net/html"
+)
+
+// updateFlag is the flag rewriting the golden files. It is registered by
+// gtest unless a package initialized before it already defines it, in which
+// case Snapshot uses that flag. Tests with golden files of their own can
+// share it with flag.Lookup(updateFlag) instead of defining it again.
+const updateFlag = "update"
+
+func init() {
+	if flag.Lookup(updateFlag) == nil {
+		flag.Bool(updateFlag, false, "rewrite the golden files of gtest.Snapshot")
+	}
+}
+
+// updating reports whether the golden files are to be written.
+func updating() bool {
+	if os.Getenv("GTEST_UPDATE") != "" {
+		return true
+	}
+	f := flag.Lookup(updateFlag)
+	return f != nil && f.Value.String() == "true"
+}
+
+// goldenDir is the directory of the golden files, relative to the package
+// under test.
+var goldenDir = "testdata"
+
+var (
+	snapshotsMu sync.Mutex
+	snapshots   = map[string]int{} // number of snapshots taken, by running test
+)
+
+// Snapshot renders node in a canonical pretty format and compares it with
+// the golden file testdata/<test name>.golden, reporting a line diff on
+// mismatch. When a test takes several snapshots, the second one is stored
+// in <test name>_2.golden, and so on.
+//
+// With the -update flag, the golden file is written instead.
+func Snapshot(t testing.TB, node g.Node) {
+	t.Helper()
+
+	got, err := pretty(node)
+	if err != nil {
+		t.Fatalf("gtest.Snapshot: render: %v", err)
+	}
+
+	path := filepath.Join(goldenDir, goldenName(t)+".golden")
+	if updating() {
+		if err := os.MkdirAll(goldenDir, 0o755); err != nil {
+			t.Fatalf("gtest.Snapshot: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("gtest.Snapshot: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("gtest.Snapshot: golden file %s doesn't exist; run the test with -update to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("gtest.Snapshot: %v", err)
+	}
+	if string(want) != got {
+		t.Errorf("gtest.Snapshot: output doesn't match %s (-want +got):\n%s", path, lineDiff(string(want), got))
+	}
+}
+
+// goldenName returns the file name, without extension, of the next snapshot
+// of the test. The count restarts when the test ends, so that -count=2 runs
+// find the same files.
+func goldenName(t testing.TB) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, t.Name())
+
+	snapshotsMu.Lock()
+	defer snapshotsMu.Unlock()
+	testName := t.Name()
+	snapshots[testName]++
+	n := snapshots[testName]
+	if n == 1 {
+		t.Cleanup(func() {
+			snapshotsMu.Lock()
+			defer snapshotsMu.Unlock()
+			delete(snapshots, testName)
+		})
+	}
+	if n > 1 {
+		name = fmt.Sprintf("%s_%d", name, n)
+	}
+	return name
+}
+
+// pretty renders node with one tag or text per line, indented by depth.
+// Elements holding a single text are kept on one line.
+//
+// Whitespace is collapsed and trimmed at the start and end of elements,
+// where browsers don't render it, and whitespace-only text is dropped
+// there. Text keeping whitespace that matters, next to other content or in
+// a Pre, is written as a quoted Go string, so that it shows in diffs.
+func pretty(node g.Node) (string, error) {
+	s, err := node.Render()
+	if err != nil {
+		return "", err
+	}
+
+	var tokens []nethtml.Token
+	z := nethtml.NewTokenizer(strings.NewReader(s))
+	for z.Next() != nethtml.ErrorToken {
+		tokens = append(tokens, z.Token())
+	}
+
+	var sb strings.Builder
+	depth := 0
+	line := func(s string) {
+		sb.WriteString(strings.Repeat("  ", depth))
+		sb.WriteString(s)
+		sb.WriteString("\n")
+	}
+	rawText := false // inside script or style, where text isn't escaped
+	pre := 0         // depth of elements keeping their whitespace
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.Type {
+		case nethtml.StartTagToken:
+			rawText = tok.Data == "script" || tok.Data == "style"
+			if g.IsVoidTag(tok.Data) {
+				line(startTag(tok))
+				continue
+			}
+			if preTags[tok.Data] {
+				pre++
+			}
+			// <p>text</p> on a single line
+			if i+2 < len(tokens) && tokens[i+1].Type == nethtml.TextToken && tokens[i+2].Type == nethtml.EndTagToken && tokens[i+2].Data == tok.Data {
+				if t, ok := text(tokens, i+1, rawText, pre > 0); ok {
+					line(startTag(tok) + t + "</" + tok.Data + ">")
+					rawText = false
+					if preTags[tok.Data] {
+						pre--
+					}
+					i += 2
+					continue
+				}
+			}
+			line(startTag(tok))
+			depth++
+		case nethtml.EndTagToken:
+			rawText = false
+			if preTags[tok.Data] && pre > 0 {
+				pre--
+			}
+			depth = max(depth-1, 0)
+			line("</" + tok.Data + ">")
+		case nethtml.SelfClosingTagToken:
+			line(startTag(tok))
+		case nethtml.TextToken:
+			if t, ok := text(tokens, i, rawText, pre > 0); ok {
+				line(t)
+			}
+		case nethtml.CommentToken:
+			line("<!--" + tok.Data + "-->")
+		case nethtml.DoctypeToken:
+			line("<!DOCTYPE " + tok.Data + ">")
+		}
+	}
+	return sb.String(), nil
+}
+
+// preTags are the elements whose text keeps its whitespace.
+var preTags = map[string]bool{"pre": true, "textarea": true, "listing": true}
+
+func startTag(tok nethtml.Token) string {
+	var sb strings.Builder
+	sb.WriteString("<" + tok.Data)
+	for _, attr := range tok.Attr {
+		sb.WriteString(" " + attr.Key)
+		if attr.Val != "" {
+			sb.WriteString(`="` + html.EscapeString(attr.Val) + `"`)
+		}
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+// text returns the line of the text token i, and whether it has one.
+func text(tokens []nethtml.Token, i int, raw, pre bool) (string, bool) {
+	s := tokens[i].Data
+	switch {
+	case pre:
+		return strconv.Quote(html.EscapeString(s)), s != ""
+	case raw:
+		s = strings.TrimSpace(s)
+		return s, s != ""
+	}
+
+	s = g.CollapseSpace(s)
+	// whitespace at the start or the end of an element isn't rendered
+	if i == 0 || tokens[i-1].Type == nethtml.StartTagToken && !g.IsVoidTag(tokens[i-1].Data) {
+		s = strings.TrimLeft(s, " ")
+	}
+	if i == len(tokens)-1 || tokens[i+1].Type == nethtml.EndTagToken {
+		s = strings.TrimRight(s, " ")
+	}
+	if s == "" {
+		return "", false
+	}
+	s = html.EscapeString(s)
+	if strings.HasPrefix(s, " ") || strings.HasSuffix(s, " ") {
+		return strconv.Quote(s), true
+	}
+	return s, true
+}
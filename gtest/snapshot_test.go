@@ -0,0 +1,195 @@
+package gtest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+func TestPretty(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     g.Node
+		expected string
+	}{
+		{
+			name:     "Single text child on one line",
+			node:     g.P(g.KV{"class": "a"}, g.Text("Hello")),
+			expected: "<p class=\"a\">Hello</p>\n",
+		},
+		{
+			name: "Nested elements",
+			node: g.Div(g.H1(g.Text("Title")), g.P(g.Text("a "), g.Strong(g.Text("b")), g.Br()), g.Input(g.KV{"required": true})),
+			expected: "<div>\n" +
+				"  <h1>Title</h1>\n" +
+				"  <p>\n" +
+				"    \"a \"\n" +
+				"    <strong>b</strong>\n" +
+				"    <br>\n" +
+				"  </p>\n" +
+				"  <input required>\n" +
+				"</div>\n",
+		},
+		{
+			name:     "Escaping",
+			node:     g.Empty(g.P(g.KV{"title": `"x"`}, g.Text("a < b")), g.Script(g.Text("if (a < b) {}"))),
+			expected: "<p title=\"&#34;x&#34;\">a &lt; b</p>\n<script>if (a &lt; b) {}</script>\n",
+		},
+		{
+			name:     "Space next to other content",
+			node:     g.Empty(g.P(g.Text("a"), g.B(g.Text("b"))), g.P(g.Em(g.Text("c")), g.Text(" "), g.Em(g.Text("d"))), g.P(g.Text(" e "))),
+			expected: "<p>\n  a\n  <b>b</b>\n</p>\n<p>\n  <em>c</em>\n  \" \"\n  <em>d</em>\n</p>\n<p>e</p>\n",
+		},
+		{
+			name:     "Pre keeps whitespace",
+			node:     g.Pre(g.Code(g.CodeText("if a {\n  b()\n}"))),
+			expected: "<pre>\n  <code>\"if a {\\n  b()\\n}\"</code>\n</pre>\n",
+		},
+		{
+			name:     "Empty elements",
+			node:     g.Ul(g.Li(), g.Li(g.Text(" "))),
+			expected: "<ul>\n  <li>\n  </li>\n  <li>\n  </li>\n</ul>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := pretty(tt.node)
+			if err != nil {
+				t.Fatalf("pretty() error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("pretty() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	want := "a\nb\nc\nd\ne\nf\ng\nh\ni\n"
+	got := "a\nb\nc\nd\nE\nf\ng\nh\ni\nj\n"
+	expected := "  b\n  c\n  d\n- e\n+ E\n  f\n  g\n  h\n  i\n+ j\n"
+	if result := lineDiff(want, got); result != "...\n"+expected {
+		t.Errorf("lineDiff() = %q, want %q", result, "...\n"+expected)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	Snapshot(t, g.Form(
+		g.Label(g.KV{"for": "q"}, g.Text("Search")),
+		g.Input(g.KV{"id": "q", "name": "q"}),
+		g.Button(g.Text("Go")),
+	))
+	Snapshot(t, g.P(g.Text("second snapshot")))
+}
+
+// recorder is a testing.TB that records failures instead of failing.
+type recorder struct {
+	testing.TB
+	name     string
+	failures []string
+}
+
+func (me *recorder) Name() string { return me.name }
+func (me *recorder) Helper()      {}
+
+func (me *recorder) Errorf(format string, args ...any) {
+	me.failures = append(me.failures, fmt.Sprintf(format, args...))
+}
+
+func (me *recorder) Fatalf(format string, args ...any) {
+	me.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// record runs fn with a recorder in its own goroutine, so that Fatalf can
+// stop it.
+func record(t *testing.T, name string, fn func(testing.TB)) []string {
+	r := &recorder{TB: t, name: name}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(r)
+	}()
+	<-done
+	return r.failures
+}
+
+func TestSnapshot_Golden(t *testing.T) {
+	old := goldenDir
+	goldenDir = t.TempDir()
+	defer func() { goldenDir = old }()
+
+	node := g.Ul(g.Li(g.Text("a")), g.Li(g.Text("b")))
+
+	failures := record(t, "TestCase/missing", func(tb testing.TB) { Snapshot(tb, node) })
+	if len(failures) != 1 || !strings.Contains(failures[0], "run the test with -update") {
+		t.Errorf("Snapshot() without golden = %q, want a hint about -update", failures)
+	}
+
+	flag.Set(updateFlag, "true")
+	failures = record(t, "TestCase/updated", func(tb testing.TB) { Snapshot(tb, node) })
+	flag.Set(updateFlag, "false")
+	if len(failures) != 0 {
+		t.Fatalf("Snapshot() with -update = %q, want no failures", failures)
+	}
+	data, err := os.ReadFile(filepath.Join(goldenDir, "TestCase_updated.golden"))
+	if err != nil || string(data) != "<ul>\n  <li>a</li>\n  <li>b</li>\n</ul>\n" {
+		t.Errorf("golden file = %q, %v", data, err)
+	}
+
+	failures = record(t, "TestCase/updated", func(tb testing.TB) { Snapshot(tb, node) })
+	if len(failures) != 1 || !strings.Contains(failures[0], "_2.golden") {
+		t.Errorf("second Snapshot() = %q, want a separate golden file", failures)
+	}
+
+	snapshots["TestCase/updated"] = 0
+	failures = record(t, "TestCase/updated", func(tb testing.TB) { Snapshot(tb, g.Ul(g.Li(g.Text("a")), g.Li(g.Text("c")))) })
+	if len(failures) != 1 || !strings.Contains(failures[0], "-   <li>b</li>\n+   <li>c</li>") {
+		t.Errorf("Snapshot() mismatch = %q, want a diff", failures)
+	}
+}
+
+// cleanupTB is a testing.TB whose cleanups run when end is called.
+type cleanupTB struct {
+	testing.TB
+	cleanups []func()
+}
+
+func (me *cleanupTB) Name() string      { return "TestCase/run" }
+func (me *cleanupTB) Cleanup(fn func()) { me.cleanups = append(me.cleanups, fn) }
+
+func (me *cleanupTB) end() {
+	for _, fn := range me.cleanups {
+		fn()
+	}
+}
+
+func TestGoldenName_RestartsWithEachRun(t *testing.T) {
+	for range 2 { // like go test -count=2
+		tb := &cleanupTB{TB: t}
+		if name := goldenName(tb); name != "TestCase_run" {
+			t.Errorf("goldenName() = %q, want %q", name, "TestCase_run")
+		}
+		if name := goldenName(tb); name != "TestCase_run_2" {
+			t.Errorf("second goldenName() = %q, want %q", name, "TestCase_run_2")
+		}
+		tb.end()
+	}
+}
+
+func TestUpdating(t *testing.T) {
+	if updating() {
+		t.Skip("the tests run with -update")
+	}
+	t.Setenv("GTEST_UPDATE", "1")
+	if !updating() {
+		t.Error("updating() = false with GTEST_UPDATE set, want true")
+	}
+}
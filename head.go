@@ -0,0 +1,229 @@
+package g
+
+import (
+	"slices"
+	"strings"
+)
+
+// HeadItem marks node, such as a Script, Link, Meta or Title, as belonging
+// in the Head of the document, wherever it is in the tree. It lets a
+// component declare the assets it needs next to its markup.
+//
+// When the document is rendered, the head items found anywhere in it are
+// appended to its Head, and render nothing where they are. Items are
+// deduplicated with the content of Head and between them:
+//   - a Script is kept once by src, and a Link once by rel and href,
+//   - a Title, Base, canonical Link, or Meta with the same name, charset,
+//     http-equiv or single-valued OpenGraph property (such as og:title)
+//     replaces the one already in Head, so that a page can override the
+//     defaults of its layout,
+//   - a Meta with another property, which OpenGraph allows to repeat, is
+//     always kept, except that og:image, og:video and og:audio items with
+//     their structured properties, such as og:image:width, replace the
+//     ones of Head as a group: the images of a page replace the default
+//     image of its layout, and several images are kept when they all come
+//     from head items,
+//   - other nodes are kept once by their rendered HTML.
+//
+// Items of a tree without Head, such as a fragment, render where they are.
+//
+// Example:
+//
+//	func Chart(data string) Node {
+//		return Empty(
+//			HeadItem(Script(KV{"src": "/chart.js", "defer": true})),
+//			Canvas(KV{"data-points": data}),
+//		)
+//	}
+//
+//	func ProductPage(p Product) Node {
+//		return Layout(Empty(
+//			HeadItem(Title(Text(p.Name))),
+//			Chart(p.Sales), Chart(p.Returns),
+//		))
+//	}
+//	// the Head of Layout gets one chart.js script, and its title is p.Name
+func HeadItem(node Node) Node {
+	return &headItem{node: node}
+}
+
+// UnwrapHeadItem returns the node marked by HeadItem, and whether node is
+// a head item.
+//
+// Example:
+//
+//	UnwrapHeadItem(HeadItem(Title(Text("T")))) // Title(Text("T")), true
+//	UnwrapHeadItem(Title(Text("T")))           // nil, false
+func UnwrapHeadItem(node Node) (Node, bool) {
+	item, ok := node.(*headItem)
+	if !ok {
+		return nil, false
+	}
+	return item.node, true
+}
+
+type headItem struct {
+	node Node
+}
+
+func (me *headItem) Render() (string, error) {
+	return me.node.Render()
+}
+
+// hoist returns the children of head, the Head of the document being
+// rendered, merged with the head items of the document.
+func (me *renderer) hoist(head *Element) ([]Node, error) {
+	root := me.root
+	if root == nil {
+		root = head
+	}
+	var items []Node
+	collectHeadItems(root, &items)
+	if len(items) == 0 {
+		return head.Children, nil
+	}
+
+	items = Flatten(items)
+	replaced := map[string]bool{}
+	for _, item := range items {
+		if group := me.headGroup(item); group != "" {
+			replaced[group] = true
+		}
+	}
+	result := slices.DeleteFunc(Flatten(head.Children), func(node Node) bool {
+		return replaced[me.headGroup(node)]
+	})
+	index := map[string]int{}
+	for i, child := range result {
+		key, _, err := me.headKey(child)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := index[key]; key != "" && !ok {
+			index[key] = i
+		}
+	}
+	for _, item := range items {
+		key, replace, err := me.headKey(item)
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := index[key]; ok {
+			if replace {
+				result[i] = item
+			}
+			continue
+		}
+		if key != "" {
+			index[key] = len(result)
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// collectHeadItems appends the nodes of the head items of node to items, in
+// document order.
+func collectHeadItems(node Node, items *[]Node) {
+	switch n := node.(type) {
+	case *headItem:
+		*items = append(*items, n.node)
+	case *Element:
+		if strings.EqualFold(n.Tag, "template") {
+			return
+		}
+		for _, child := range n.Children {
+			collectHeadItems(child, items)
+		}
+	}
+}
+
+// headSingleProperties are the OpenGraph properties a page has at most
+// once. The others, such as og:image, og:locale:alternate or article:tag,
+// are arrays, and the structured properties following an array item, such
+// as og:image:width, describe that item.
+var headSingleProperties = map[string]bool{
+	"og:title":                true,
+	"og:type":                 true,
+	"og:url":                  true,
+	"og:description":          true,
+	"og:site_name":            true,
+	"og:locale":               true,
+	"og:determiner":           true,
+	"fb:app_id":               true,
+	"article:published_time":  true,
+	"article:modified_time":   true,
+	"article:expiration_time": true,
+	"article:section":         true,
+}
+
+// headGroups are the OpenGraph arrays that head items replace as a whole:
+// their items and the structured properties describing them.
+var headGroups = []string{"og:image", "og:video", "og:audio"}
+
+// headGroup returns the OpenGraph array node belongs to, or "".
+func (me *renderer) headGroup(node Node) string {
+	e, ok := node.(*Element)
+	if !ok || !strings.EqualFold(e.Tag, "meta") {
+		return ""
+	}
+	attrs, _ := renderedAttrs(e.Attrs, me) // errors are reported by headKey
+	property := strings.ToLower(strings.TrimSpace(attrs["property"]))
+	for _, group := range headGroups {
+		if property == group || strings.HasPrefix(property, group+":") {
+			return group
+		}
+	}
+	return ""
+}
+
+// headKey returns the key deduplicating node in Head, or "" for whitespace
+// and the nodes that are always kept, and whether a node with the same key
+// replaces the previous one.
+func (me *renderer) headKey(node Node) (string, bool, error) {
+	if t, ok := node.(Text); ok && strings.TrimSpace(string(t)) == "" {
+		return "", false, nil
+	}
+	if e, ok := node.(*Element); ok {
+		attrs, err := renderedAttrs(e.Attrs, me)
+		if err != nil {
+			return "", false, err
+		}
+		switch tag := strings.ToLower(e.Tag); tag {
+		case "title", "base":
+			return tag, true, nil
+		case "meta":
+			if _, ok := attrs["charset"]; ok {
+				return "meta charset", true, nil
+			}
+			for _, k := range []string{"name", "http-equiv"} {
+				if v, ok := attrs[k]; ok {
+					return "meta " + k + "=" + strings.ToLower(v), true, nil
+				}
+			}
+			if v, ok := attrs["property"]; ok {
+				if v = strings.ToLower(strings.TrimSpace(v)); headSingleProperties[v] {
+					return "meta property=" + v, true, nil
+				}
+				return "", false, nil
+			}
+		case "link":
+			rel := strings.Fields(strings.ToLower(attrs["rel"]))
+			if slices.Contains(rel, "canonical") {
+				return "link canonical", true, nil
+			}
+			if href, ok := attrs["href"]; ok {
+				return "link " + strings.Join(rel, " ") + " " + href, false, nil
+			}
+		case "script":
+			if src, ok := attrs["src"]; ok {
+				return "script " + src, false, nil
+			}
+		}
+	}
+	s, err := me.renderNode(node)
+	if err != nil {
+		return "", false, err
+	}
+	return "html " + s, false, nil
+}
@@ -0,0 +1,124 @@
+package g
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHeadItem(t *testing.T) {
+	layout := func(content ...any) Node {
+		return Html(
+			Head(
+				Meta(KV{"charset": "utf-8"}),
+				Title(Text("Site")),
+				Meta(KV{"name": "description", "content": "default"}),
+				Script(KV{"src": "/app.js"}),
+			),
+			Body(content...),
+		)
+	}
+	chart := func() Node {
+		return Empty(HeadItem(Script(KV{"src": "/chart.js"})), Canvas())
+	}
+
+	tests := []struct {
+		name     string
+		node     Node
+		expected string
+	}{
+		{
+			name:     "Items are appended to head once",
+			node:     layout(chart(), chart(), HeadItem(Style(Text("p{}"))), HeadItem(Style(Text("p{}")))),
+			expected: `<html><head><meta charset="utf-8"><title>Site</title><meta content="default" name="description"><script src="/app.js"></script><script src="/chart.js"></script><style>p{}</style></head><body><canvas></canvas><canvas></canvas></body></html>`,
+		},
+		{
+			name:     "Items override the defaults of head",
+			node:     layout(HeadItem(Empty(Title(Text("Page")), Meta(KV{"name": "Description", "content": "page"}))), HeadItem(Script(KV{"src": "/app.js"}))),
+			expected: `<html><head><meta charset="utf-8"><title>Page</title><meta content="page" name="Description"><script src="/app.js"></script></head><body></body></html>`,
+		},
+		{
+			name:     "Last override wins",
+			node:     layout(HeadItem(Title(Text("A"))), Div(HeadItem(Title(Text("B"))))),
+			expected: `<html><head><meta charset="utf-8"><title>B</title><meta content="default" name="description"><script src="/app.js"></script></head><body><div></div></body></html>`,
+		},
+		{
+			name:     "Links are deduplicated by rel and href",
+			node:     Html(Head(), Body(HeadItem(Link(KV{"rel": "stylesheet", "href": "/a.css"})), HeadItem(Link(KV{"rel": "preload", "href": "/a.css"})), HeadItem(Link(KV{"rel": "stylesheet", "href": "/a.css"})))),
+			expected: `<html><head><link href="/a.css" rel="stylesheet"><link href="/a.css" rel="preload"></head><body></body></html>`,
+		},
+		{
+			name: "Single OpenGraph properties and canonical links are overridden",
+			node: Html(
+				Head(Meta(KV{"property": "og:title", "content": "Site"}), Link(KV{"rel": "canonical", "href": "/"})),
+				Body(HeadItem(Empty(Meta(KV{"property": "og:title", "content": "Page"}), Link(KV{"rel": "canonical", "href": "/page"})))),
+			),
+			expected: `<html><head><meta content="Page" property="og:title"><link href="/page" rel="canonical"></head><body></body></html>`,
+		},
+		{
+			name: "OpenGraph images replace those of head",
+			node: Html(
+				Head(Meta(KV{"property": "og:image", "content": "/default.png"}), Meta(KV{"property": "og:image:width", "content": "600"}), Meta(KV{"property": "og:title", "content": "Site"})),
+				Body(HeadItem(Empty(Meta(KV{"property": "og:image", "content": "/cover.png"}), Meta(KV{"property": "og:image:alt", "content": "Cover"})))),
+			),
+			expected: `<html><head><meta content="Site" property="og:title"><meta content="/cover.png" property="og:image"><meta content="Cover" property="og:image:alt"></head><body></body></html>`,
+		},
+		{
+			name: "OpenGraph images of head items accumulate",
+			node: Html(
+				Head(Meta(KV{"property": "og:image", "content": "/default.png"})),
+				Body(
+					HeadItem(Empty(Meta(KV{"property": "og:image", "content": "/a.png"}), Meta(KV{"property": "og:image:width", "content": "1200"}))),
+					HeadItem(Empty(Meta(KV{"property": "og:image", "content": "/b.png"}), Meta(KV{"property": "og:image:width", "content": "1200"}))),
+				),
+			),
+			expected: `<html><head><meta content="/a.png" property="og:image"><meta content="1200" property="og:image:width"><meta content="/b.png" property="og:image"><meta content="1200" property="og:image:width"></head><body></body></html>`,
+		},
+		{
+			name: "Other OpenGraph arrays accumulate",
+			node: Html(
+				Head(Meta(KV{"property": "og:image", "content": "/default.png"}), Meta(KV{"property": "article:tag", "content": "go"})),
+				Body(HeadItem(Meta(KV{"property": "article:tag", "content": "html"}))),
+			),
+			expected: `<html><head><meta content="/default.png" property="og:image"><meta content="go" property="article:tag"><meta content="html" property="article:tag"></head><body></body></html>`,
+		},
+		{
+			name:     "Items in templates are not hoisted",
+			node:     Html(Head(), Body(Template(HeadItem(Script(KV{"src": "/x.js"}))))),
+			expected: `<html><head></head><body><template><script src="/x.js"></script></template></body></html>`,
+		},
+		{
+			name:     "Without head, items render in place",
+			node:     Div(HeadItem(Script(KV{"src": "/x.js"})), P(Text("a"))),
+			expected: `<div><script src="/x.js"></script><p>a</p></div>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.node.Render()
+			if err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Render() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHeadItem_URLPolicy(t *testing.T) {
+	page := Html(Head(), Body(HeadItem(Script(KV{"src": "javascript:alert(1)"}))))
+	if err := Render(&strings.Builder{}, page, WithURLPolicy(URLError)); err == nil {
+		t.Errorf("Render() error = nil, want an unsafe URL error")
+	}
+}
+
+func TestUnwrapHeadItem(t *testing.T) {
+	title := Title(Text("T"))
+	if node, ok := UnwrapHeadItem(HeadItem(title)); !ok || node != title {
+		t.Errorf("UnwrapHeadItem(HeadItem(title)) = %v, %v, want title, true", node, ok)
+	}
+	if node, ok := UnwrapHeadItem(title); ok || node != nil {
+		t.Errorf("UnwrapHeadItem(title) = %v, %v, want nil, false", node, ok)
+	}
+}
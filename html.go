@@ -3,9 +3,11 @@ package g
 import (
 	"fmt"
 	"html"
+	"maps"
 	"slices"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // Text represents a plain text node that renders HTML-escaped content.
@@ -35,19 +37,43 @@ func (me Text) Render() (string, error) {
 	return html.EscapeString(s), nil
 }
 
+// CodeText is a text node that keeps its whitespace when rendered, for the
+// content of Pre and Textarea elements, where browsers keep it too. Like
+// Text, it is HTML-escaped.
+//
+// Example:
+//
+//	Pre(Code(CodeText("if ok {\n\treturn\n}")))
+type CodeText string
+
+func (me CodeText) Render() (string, error) {
+	return html.EscapeString(string(me)), nil
+}
+
 // KV represents a key-value map for HTML attributes.
 //
-// The value type must be either string or bool:
+// The value type must be either string, bool, *ID or AttrValuer:
 //   - string: Attribute will have the format key="value" (HTML-escaped)
+//   - *ID: Its value is generated at render time (see UniqueID).
 //   - bool: If true, attribute appears as key (valueless). If false, attribute is omitted.
+//   - AttrValuer: Its value is computed at render time and rendered like a string.
 //   - any other type triggers an error during rendering.
 //
+// When an element gets several KV arguments, they are merged, and later
+// keys override earlier ones.
+//
 // Example:
 //
 //	KV{"class": "container", "hidden": true, "disabled": false}
 //	// Renders: class="container" hidden
 type KV map[string]any
 
+// AttrValuer is implemented by attribute values that are computed at render
+// time. An error returned by AttrValue makes the rendering fail.
+type AttrValuer interface {
+	AttrValue() (string, error)
+}
+
 // Element represents an HTML element with its attributes and children.
 type Element struct {
 	Tag      string // HTML tag name
@@ -66,44 +92,84 @@ type Element struct {
 //
 // Returns the complete HTML string as byteslice and any error encountered.
 func (me *Element) Render() (string, error) {
-	builder := &strings.Builder{}
+	r := newRenderer()
+	r.root = me
+	return r.renderNode(me)
+}
+
+// Add appends children to the element and returns it for chaining.
+//
+// Void elements (e.g., <br>, <img>) cannot have children, so Add ignores
+// the children passed to them.
+//
+// Example:
+//
+//	Div().Add(P(Text("Hello")), P(Text("World")))
+func (me *Element) Add(children ...Node) Node {
+	if me.IsVoid {
+		return me
+	}
+	me.Children = append(me.Children, children...)
+	return me
+}
 
+func (me *Element) render(builder *strings.Builder, r *renderer) error {
 	if me.Tag == "" { // empty tag
-		if err := me.renderChildren(builder); err != nil {
-			return "", err
-		}
-		return builder.String(), nil
+		return r.renderChildren(builder, me.Children)
 	}
 
+	if !isValidTag(me.Tag) {
+		return fmt.Errorf("invalid tag name %q", me.Tag)
+	}
 	fmt.Fprint(builder, "<")
 	fmt.Fprint(builder, me.Tag)
-	if err := me.renderAttrs(builder); err != nil {
-		return "", err
+	if err := me.renderAttrs(builder, r); err != nil {
+		return err
 	}
 	fmt.Fprint(builder, ">")
 
 	if me.IsVoid {
-		return builder.String(), nil
+		return nil
 	}
 
-	if err := me.renderChildren(builder); err != nil {
-		return "", nil
+	children := me.Children
+	switch {
+	case strings.EqualFold(me.Tag, "head") && !r.hoisted:
+		r.hoisted = true
+		var err error
+		if children, err = r.hoist(me); err != nil {
+			return err
+		}
+	case strings.EqualFold(me.Tag, "template") && r.hoisted:
+		// the content of a template isn't part of the document
+		r.hoisted = false
+		defer func() { r.hoisted = true }()
+	}
+	if err := r.renderChildren(builder, children); err != nil {
+		return err
 	}
 	fmt.Fprintf(builder, "</%s>", me.Tag)
 
-	return builder.String(), nil
+	return nil
 }
 
-func (me Element) renderAttrs(builder *strings.Builder) error {
+func (me Element) renderAttrs(builder *strings.Builder, r *renderer) error {
 	// for deterministic attrs order
 	type kv struct {
 		key   string
 		value any
 	}
-	attrSlice := make([]kv, 0, len(me.Attrs))
+	attrSlice := make([]kv, 0, len(me.Attrs)+1)
+	nonce := r.nonceAttr(me.Tag)
 	for key, value := range me.Attrs {
+		if nonce != "" && strings.EqualFold(strings.TrimSpace(key), "nonce") {
+			continue
+		}
 		attrSlice = append(attrSlice, kv{key, value})
 	}
+	if nonce != "" {
+		attrSlice = append(attrSlice, kv{"nonce", nonce})
+	}
 	slices.SortFunc(attrSlice, func(a, b kv) int {
 		return strings.Compare(a.key, b.key)
 	})
@@ -111,29 +177,99 @@ func (me Element) renderAttrs(builder *strings.Builder) error {
 	for _, attr := range attrSlice {
 		k := strings.TrimSpace(attr.key)
 		if k == "" {
-			return fmt.Errorf("empty/whitespace attribute key not allowed.")
+			return fmt.Errorf("empty/whitespace attribute key not allowed on <%s>", me.Tag)
+		}
+		if !isValidAttrKey(k) {
+			return fmt.Errorf("invalid attribute key %q on <%s>", k, me.Tag)
 		}
 		if attr.value == nil {
 			return fmt.Errorf("attribute '%s' has nil value", k)
 		}
 
-		switch v := attr.value.(type) {
-		case string:
-			fmt.Fprintf(builder, ` %s="%s"`, k, html.EscapeString(v))
-		case bool:
+		if v, ok := attr.value.(bool); ok {
 			if v == true {
 				fmt.Fprintf(builder, " %s", k)
 			}
-		default:
-			return fmt.Errorf("attribute value must be string or bool, got %T for key '%s'", v, k)
+			continue
+		}
+		v, err := r.attrString(me.Tag, k, attr.value)
+		if err != nil {
+			return err
 		}
+		fmt.Fprintf(builder, ` %s="%s"`, k, html.EscapeString(v))
 	}
 
 	return nil
 }
 
-func (me Element) renderChildren(builder *strings.Builder) error {
-	for _, child := range me.Children {
+// isValidAttrKey reports whether key follows the HTML attribute name
+// grammar: no control characters, spaces, quotes, '>', '/', '=' or
+// noncharacters. Anything else could end the attribute, or the tag, early.
+//
+// https://html.spec.whatwg.org/multipage/syntax.html#attributes-2
+func isValidAttrKey(key string) bool {
+	if key == "" || !utf8.ValidString(key) {
+		return false
+	}
+	for _, r := range key {
+		switch {
+		case unicode.IsControl(r), unicode.IsSpace(r):
+			return false
+		case r == '"', r == '\'', r == '>', r == '/', r == '=':
+			return false
+		case r >= 0xFDD0 && r <= 0xFDEF, r&0xFFFE == 0xFFFE:
+			return false
+		}
+	}
+	return true
+}
+
+// isValidTag reports whether tag is a valid element name: an ASCII letter
+// followed by ASCII letters and digits, or by the characters allowed in
+// custom element names. Anything else could end the tag early, or add
+// attributes to it.
+//
+// https://html.spec.whatwg.org/multipage/custom-elements.html#valid-custom-element-name
+func isValidTag(tag string) bool {
+	if tag == "" || !isASCIILetter(tag[0]) || !utf8.ValidString(tag) {
+		return false
+	}
+	for _, r := range tag[1:] {
+		switch {
+		case r < utf8.RuneSelf:
+			if !isASCIILetter(byte(r)) && (r < '0' || r > '9') && r != '-' && r != '.' && r != '_' {
+				return false
+			}
+		case r == 0xB7,
+			r >= 0xC0 && r <= 0xD6, r >= 0xD8 && r <= 0xF6, r >= 0xF8 && r <= 0x37D,
+			r >= 0x37F && r <= 0x1FFF, r == 0x200C, r == 0x200D, r == 0x203F, r == 0x2040,
+			r >= 0x2070 && r <= 0x218F, r >= 0x2C00 && r <= 0x2FEF, r >= 0x3001 && r <= 0xD7FF,
+			r >= 0xF900 && r <= 0xFDCF, r >= 0xFDF0 && r <= 0xFFFD, r >= 0x10000 && r <= 0xEFFFF:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func (me *renderer) renderChildren(builder *strings.Builder, children []Node) error {
+	for _, child := range children {
+		if item, ok := child.(*headItem); ok {
+			if me.hoisted {
+				continue // rendered in Head
+			}
+			child = item.node
+		}
+		if e, ok := child.(*Element); ok {
+			if err := e.render(builder, me); err != nil {
+				return err
+			}
+			continue
+		}
 		s, err := child.Render()
 		if err != nil {
 			return err
@@ -148,7 +284,7 @@ func newElem(tag string, args ...any) *Element {
 	for _, arg := range args {
 		switch value := arg.(type) {
 		case KV:
-			e.Attrs = value // this overrides the old ones if duplicates are passed
+			e.Attrs = mergeAttrs(e.Attrs, value)
 		case Node:
 			e.Children = append(e.Children, value)
 		default: // ignore
@@ -157,11 +293,42 @@ func newElem(tag string, args ...any) *Element {
 	return e
 }
 
+// voidTags are the elements that can't have children.
+var voidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "source": true,
+	"track": true, "wbr": true,
+}
+
+// IsVoidTag reports whether tag, in any case, names a void element, which
+// has no end tag and can't have children.
+//
+// Example:
+//
+//	IsVoidTag("BR")  // true
+//	IsVoidTag("div") // false
+//
+// https://developer.mozilla.org/en-US/docs/Glossary/Void_element
+func IsVoidTag(tag string) bool {
+	return voidTags[strings.ToLower(tag)]
+}
+
 func newVoidElem(tag string, attrs ...KV) *Element {
-	if len(attrs) > 0 {
-		return &Element{Tag: tag, Attrs: attrs[0]}
+	e := &Element{Tag: tag, IsVoid: true}
+	for _, kv := range attrs {
+		e.Attrs = mergeAttrs(e.Attrs, kv)
+	}
+	return e
+}
+
+// mergeAttrs copies src into dst, allocating dst if needed. Keys in src
+// override the ones already in dst.
+func mergeAttrs(dst, src KV) KV {
+	if dst == nil {
+		dst = make(KV, len(src))
 	}
-	return &Element{Tag: tag}
+	maps.Copy(dst, src)
+	return dst
 }
 
 // Empty creates an empty element (no tag).
@@ -169,6 +336,15 @@ func Empty(args ...any) *Element {
 	return newElem("", args...)
 }
 
+// CustomElement creates an element with an arbitrary tag name, such as
+// custom elements defined by JavaScript libraries. Rendering fails when tag
+// isn't a valid element name, such as "x onload=y".
+//
+// https://developer.mozilla.org/en-US/docs/Web/API/Web_components/Using_custom_elements
+func CustomElement(tag string, args ...any) *Element {
+	return newElem(tag, args...)
+}
+
 // Html creates the root element of an HTML document.
 //
 // https://developer.mozilla.org/en-US/docs/Web/HTML/Reference/Elements/html
@@ -0,0 +1,311 @@
+// Package htmx provides typed helpers for htmx (https://htmx.org) attributes
+// and headers, and renders only part of a page when answering htmx requests.
+//
+// Every attribute helper returns a g.KV with a single key, so they can be
+// passed next to each other (and next to plain g.KV values) to any element:
+//
+//	g.Button(
+//		htmx.Post("/todos"),
+//		htmx.Target("#todos"),
+//		htmx.Swap(htmx.SwapOuterHTML),
+//		g.Text("Add"),
+//	)
+package htmx
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/assaidy/g"
+)
+
+// Get issues a GET request to url.
+//
+// https://htmx.org/attributes/hx-get/
+func Get(url string) g.KV {
+	return g.KV{"hx-get": url}
+}
+
+// Post issues a POST request to url.
+//
+// https://htmx.org/attributes/hx-post/
+func Post(url string) g.KV {
+	return g.KV{"hx-post": url}
+}
+
+// Put issues a PUT request to url.
+//
+// https://htmx.org/attributes/hx-put/
+func Put(url string) g.KV {
+	return g.KV{"hx-put": url}
+}
+
+// Patch issues a PATCH request to url.
+//
+// https://htmx.org/attributes/hx-patch/
+func Patch(url string) g.KV {
+	return g.KV{"hx-patch": url}
+}
+
+// Delete issues a DELETE request to url.
+//
+// https://htmx.org/attributes/hx-delete/
+func Delete(url string) g.KV {
+	return g.KV{"hx-delete": url}
+}
+
+// Trigger specifies the events that trigger the request, e.g., "click",
+// "keyup changed delay:500ms" or "every 2s".
+//
+// https://htmx.org/attributes/hx-trigger/
+func Trigger(triggers ...string) g.KV {
+	return g.KV{"hx-trigger": strings.Join(triggers, ", ")}
+}
+
+// Target specifies the element to swap the response into, as a CSS
+// selector or an extended selector such as "closest tr".
+//
+// https://htmx.org/attributes/hx-target/
+func Target(selector string) g.KV {
+	return g.KV{"hx-target": selector}
+}
+
+// SwapStyle is how the response is swapped into the target.
+type SwapStyle string
+
+const (
+	SwapInnerHTML   SwapStyle = "innerHTML"
+	SwapOuterHTML   SwapStyle = "outerHTML"
+	SwapTextContent SwapStyle = "textContent"
+	SwapBeforeBegin SwapStyle = "beforebegin"
+	SwapAfterBegin  SwapStyle = "afterbegin"
+	SwapBeforeEnd   SwapStyle = "beforeend"
+	SwapAfterEnd    SwapStyle = "afterend"
+	SwapDelete      SwapStyle = "delete"
+	SwapNone        SwapStyle = "none"
+)
+
+// Swap specifies how the response is swapped in. Modifiers such as
+// "swap:1s" or "scroll:top" are appended after the style.
+//
+// https://htmx.org/attributes/hx-swap/
+func Swap(style SwapStyle, modifiers ...string) g.KV {
+	return g.KV{"hx-swap": strings.Join(append([]string{string(style)}, modifiers...), " ")}
+}
+
+// SwapOOB marks the element to be swapped in out of band, by id. Use "true"
+// or a swap style, optionally followed by ":selector".
+//
+// https://htmx.org/attributes/hx-swap-oob/
+func SwapOOB(value string) g.KV {
+	return g.KV{"hx-swap-oob": value}
+}
+
+// Select picks the part of the response to swap in.
+//
+// https://htmx.org/attributes/hx-select/
+func Select(selector string) g.KV {
+	return g.KV{"hx-select": selector}
+}
+
+// SelectOOB picks parts of the response to swap in out of band.
+//
+// https://htmx.org/attributes/hx-select-oob/
+func SelectOOB(selectors ...string) g.KV {
+	return g.KV{"hx-select-oob": strings.Join(selectors, ",")}
+}
+
+// Vals adds values to the parameters submitted with the request. They are
+// encoded as JSON when the element is rendered.
+//
+// https://htmx.org/attributes/hx-vals/
+func Vals(values map[string]any) g.KV {
+	return g.KV{"hx-vals": jsonValue{values}}
+}
+
+// PushURL pushes url into the browser history. Use "true" to push the
+// request URL, or "false" to disable pushing inherited from a parent.
+//
+// https://htmx.org/attributes/hx-push-url/
+func PushURL(url string) g.KV {
+	return g.KV{"hx-push-url": url}
+}
+
+// ReplaceURL replaces the current URL in the browser location bar. Use
+// "true" to use the request URL, or "false" to disable it.
+//
+// https://htmx.org/attributes/hx-replace-url/
+func ReplaceURL(url string) g.KV {
+	return g.KV{"hx-replace-url": url}
+}
+
+// Confirm shows a confirm() dialog with message before issuing the request.
+//
+// https://htmx.org/attributes/hx-confirm/
+func Confirm(message string) g.KV {
+	return g.KV{"hx-confirm": message}
+}
+
+// Prompt shows a prompt() dialog with message before issuing the request.
+// The answer is sent in the HX-Prompt header.
+//
+// https://htmx.org/attributes/hx-prompt/
+func Prompt(message string) g.KV {
+	return g.KV{"hx-prompt": message}
+}
+
+// Boost turns links and forms inside the element into AJAX requests.
+//
+// https://htmx.org/attributes/hx-boost/
+func Boost(enabled bool) g.KV {
+	return g.KV{"hx-boost": boolString(enabled)}
+}
+
+// On handles event with an inline script, e.g., On("htmx:before-request", "...").
+//
+// https://htmx.org/attributes/hx-on/
+func On(event, script string) g.KV {
+	return g.KV{"hx-on:" + event: script}
+}
+
+// Indicator specifies the element that gets the htmx-request class while
+// the request is in flight.
+//
+// https://htmx.org/attributes/hx-indicator/
+func Indicator(selector string) g.KV {
+	return g.KV{"hx-indicator": selector}
+}
+
+// Include adds the values of other elements to the request.
+//
+// https://htmx.org/attributes/hx-include/
+func Include(selector string) g.KV {
+	return g.KV{"hx-include": selector}
+}
+
+// Params filters the parameters submitted with the request: "*", "none",
+// "not a,b" or "a,b".
+//
+// https://htmx.org/attributes/hx-params/
+func Params(filter string) g.KV {
+	return g.KV{"hx-params": filter}
+}
+
+// Headers adds headers to the request. They are encoded as JSON when the
+// element is rendered.
+//
+// https://htmx.org/attributes/hx-headers/
+func Headers(headers map[string]string) g.KV {
+	return g.KV{"hx-headers": jsonValue{headers}}
+}
+
+// Disable disables htmx processing for the element and its children.
+//
+// https://htmx.org/attributes/hx-disable/
+func Disable() g.KV {
+	return g.KV{"hx-disable": true}
+}
+
+// DisabledElt adds the disabled attribute to the selected elements while
+// the request is in flight.
+//
+// https://htmx.org/attributes/hx-disabled-elt/
+func DisabledElt(selector string) g.KV {
+	return g.KV{"hx-disabled-elt": selector}
+}
+
+// Disinherit stops the inheritance of the given attributes, or all of them
+// with "*".
+//
+// https://htmx.org/attributes/hx-disinherit/
+func Disinherit(attributes ...string) g.KV {
+	return g.KV{"hx-disinherit": strings.Join(attributes, " ")}
+}
+
+// Inherit enables the inheritance of the given attributes, or all of them
+// with "*", when inheritance is disabled by default.
+//
+// https://htmx.org/attributes/hx-inherit/
+func Inherit(attributes ...string) g.KV {
+	return g.KV{"hx-inherit": strings.Join(attributes, " ")}
+}
+
+// Encoding changes the request encoding, e.g., "multipart/form-data".
+//
+// https://htmx.org/attributes/hx-encoding/
+func Encoding(encoding string) g.KV {
+	return g.KV{"hx-encoding": encoding}
+}
+
+// Ext enables htmx extensions for the element and its children.
+//
+// https://htmx.org/attributes/hx-ext/
+func Ext(extensions ...string) g.KV {
+	return g.KV{"hx-ext": strings.Join(extensions, ",")}
+}
+
+// History prevents sensitive data from being saved in the history cache
+// when set to false.
+//
+// https://htmx.org/attributes/hx-history/
+func History(enabled bool) g.KV {
+	return g.KV{"hx-history": boolString(enabled)}
+}
+
+// HistoryElt specifies the element to snapshot and restore during history
+// navigation.
+//
+// https://htmx.org/attributes/hx-history-elt/
+func HistoryElt() g.KV {
+	return g.KV{"hx-history-elt": true}
+}
+
+// Preserve keeps the element unchanged between requests. The element must
+// have an id.
+//
+// https://htmx.org/attributes/hx-preserve/
+func Preserve() g.KV {
+	return g.KV{"hx-preserve": true}
+}
+
+// Request configures the request, e.g., `{"timeout": 100}`.
+//
+// https://htmx.org/attributes/hx-request/
+func Request(config string) g.KV {
+	return g.KV{"hx-request": config}
+}
+
+// Sync synchronizes requests between elements, e.g., "closest form:abort".
+//
+// https://htmx.org/attributes/hx-sync/
+func Sync(strategy string) g.KV {
+	return g.KV{"hx-sync": strategy}
+}
+
+// Validate validates the element with the HTML5 validation API before the
+// request is issued.
+//
+// https://htmx.org/attributes/hx-validate/
+func Validate(enabled bool) g.KV {
+	return g.KV{"hx-validate": boolString(enabled)}
+}
+
+// jsonValue is an attribute value encoded as JSON at render time.
+type jsonValue struct {
+	v any
+}
+
+func (me jsonValue) AttrValue() (string, error) {
+	b, err := json.Marshal(me.v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
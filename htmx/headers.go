@@ -0,0 +1,136 @@
+package htmx
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// IsRequest reports whether r was issued by htmx.
+//
+// https://htmx.org/reference/#request_headers
+func IsRequest(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true"
+}
+
+// IsBoosted reports whether r was issued by an element using hx-boost.
+func IsBoosted(r *http.Request) bool {
+	return r.Header.Get("HX-Boosted") == "true"
+}
+
+// IsHistoryRestoreRequest reports whether r restores a page missing from the
+// history cache.
+func IsHistoryRestoreRequest(r *http.Request) bool {
+	return r.Header.Get("HX-History-Restore-Request") == "true"
+}
+
+// CurrentURL returns the URL of the browser when the request was issued.
+func CurrentURL(r *http.Request) string {
+	return r.Header.Get("HX-Current-URL")
+}
+
+// RequestTarget returns the id of the target element, if it has one.
+func RequestTarget(r *http.Request) string {
+	return r.Header.Get("HX-Target")
+}
+
+// RequestTrigger returns the id of the element that triggered the request,
+// if it has one.
+func RequestTrigger(r *http.Request) string {
+	return r.Header.Get("HX-Trigger")
+}
+
+// RequestTriggerName returns the name of the element that triggered the
+// request, if it has one.
+func RequestTriggerName(r *http.Request) string {
+	return r.Header.Get("HX-Trigger-Name")
+}
+
+// PromptResponse returns the answer to the hx-prompt dialog.
+func PromptResponse(r *http.Request) string {
+	return r.Header.Get("HX-Prompt")
+}
+
+// SetTrigger triggers client side events as soon as the response is
+// received.
+//
+// https://htmx.org/headers/hx-trigger/
+func SetTrigger(w http.ResponseWriter, events ...string) {
+	w.Header().Set("HX-Trigger", strings.Join(events, ", "))
+}
+
+// SetTriggerDetail triggers client side events carrying details, e.g.,
+// {"showMessage": "Saved"}, as soon as the response is received.
+//
+// https://htmx.org/headers/hx-trigger/
+func SetTriggerDetail(w http.ResponseWriter, events map[string]any) error {
+	b, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("HX-Trigger", string(b))
+	return nil
+}
+
+// SetTriggerAfterSettle triggers client side events after the settle step.
+//
+// https://htmx.org/headers/hx-trigger/
+func SetTriggerAfterSettle(w http.ResponseWriter, events ...string) {
+	w.Header().Set("HX-Trigger-After-Settle", strings.Join(events, ", "))
+}
+
+// SetTriggerAfterSwap triggers client side events after the swap step.
+//
+// https://htmx.org/headers/hx-trigger/
+func SetTriggerAfterSwap(w http.ResponseWriter, events ...string) {
+	w.Header().Set("HX-Trigger-After-Swap", strings.Join(events, ", "))
+}
+
+// SetRetarget makes the response update the element matching selector
+// instead of the original target.
+func SetRetarget(w http.ResponseWriter, selector string) {
+	w.Header().Set("HX-Retarget", selector)
+}
+
+// SetReswap overrides how the response is swapped in.
+func SetReswap(w http.ResponseWriter, style SwapStyle, modifiers ...string) {
+	w.Header().Set("HX-Reswap", strings.Join(append([]string{string(style)}, modifiers...), " "))
+}
+
+// SetReselect overrides the part of the response that is swapped in.
+func SetReselect(w http.ResponseWriter, selector string) {
+	w.Header().Set("HX-Reselect", selector)
+}
+
+// SetRedirect makes the browser do a full page redirect to url.
+//
+// https://htmx.org/headers/hx-redirect/
+func SetRedirect(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Redirect", url)
+}
+
+// SetLocation makes htmx load url without a full page reload.
+//
+// https://htmx.org/headers/hx-location/
+func SetLocation(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Location", url)
+}
+
+// SetRefresh makes the browser do a full page refresh.
+func SetRefresh(w http.ResponseWriter) {
+	w.Header().Set("HX-Refresh", "true")
+}
+
+// SetPushURL pushes url into the browser history.
+//
+// https://htmx.org/headers/hx-push-url/
+func SetPushURL(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Push-Url", url)
+}
+
+// SetReplaceURL replaces the current URL in the browser location bar.
+//
+// https://htmx.org/headers/hx-replace-url/
+func SetReplaceURL(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Replace-Url", url)
+}
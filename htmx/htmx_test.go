@@ -0,0 +1,174 @@
+package htmx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+func TestAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		element  *g.Element
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Combined attributes",
+			element:  g.Button(Post("/todos"), Target("#list"), Swap(SwapOuterHTML), g.KV{"class": "btn"}, g.Text("Add")),
+			expected: `<button class="btn" hx-post="/todos" hx-swap="outerHTML" hx-target="#list">Add</button>`,
+		},
+		{
+			name:     "Swap modifiers",
+			element:  g.Div(Swap(SwapInnerHTML, "swap:1s", "scroll:top")),
+			expected: `<div hx-swap="innerHTML swap:1s scroll:top"></div>`,
+		},
+		{
+			name:     "Multiple triggers",
+			element:  g.Input(Get("/search"), Trigger("keyup changed delay:500ms", "search")),
+			expected: `<input hx-get="/search" hx-trigger="keyup changed delay:500ms, search">`,
+		},
+		{
+			name:     "JSON values",
+			element:  g.Div(Vals(map[string]any{"id": 1, "q": "<x>"})),
+			expected: `<div hx-vals="{&#34;id&#34;:1,&#34;q&#34;:&#34;\u003cx\u003e&#34;}"></div>`,
+		},
+		{
+			name:    "JSON values error",
+			element: g.Div(Vals(map[string]any{"f": func() {}})),
+			wantErr: true,
+		},
+		{
+			name:     "Boolean and event attributes",
+			element:  g.Body(Boost(true), Disable(), On("htmx:after-swap", "init()")),
+			expected: `<body hx-boost="true" hx-disable hx-on:htmx:after-swap="init()"></body>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.element.Render()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Render() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && result != tt.expected {
+				t.Errorf("Render() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	SetTrigger(w, "saved", "refresh")
+	SetRetarget(w, "#errors")
+	SetReswap(w, SwapBeforeEnd)
+	SetRedirect(w, "/login")
+	if err := SetTriggerDetail(w, map[string]any{"showMessage": "Saved"}); err != nil {
+		t.Fatalf("SetTriggerDetail() error: %v", err)
+	}
+
+	expected := map[string]string{
+		"HX-Trigger":  `{"showMessage":"Saved"}`,
+		"HX-Retarget": "#errors",
+		"HX-Reswap":   "beforeend",
+		"HX-Redirect": "/login",
+	}
+	for name, value := range expected {
+		if got := w.Header().Get(name); got != value {
+			t.Errorf("header %s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	page := func() g.Node {
+		return g.Html(
+			g.Body(
+				g.H1(g.Text("Todos")),
+				g.Ul(g.KV{"id": "list"}, g.Li(g.Text("one"))),
+				g.Div(g.KV{"id": "count"}, g.Text("1")),
+				g.P(g.KV{"id": attrValue("status")}, g.Text("ok")),
+			),
+		)
+	}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		fragment string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Regular request renders the page",
+			fragment: "list",
+			expected: `<html><body><h1>Todos</h1><ul id="list"><li>one</li></ul><div id="count">1</div><p id="status">ok</p></body></html>`,
+		},
+		{
+			name:     "htmx request renders the fragment",
+			headers:  map[string]string{"HX-Request": "true"},
+			fragment: "list",
+			expected: `<ul id="list"><li>one</li></ul>`,
+		},
+		{
+			name:     "htmx request uses the target",
+			headers:  map[string]string{"HX-Request": "true", "HX-Target": "count"},
+			expected: `1`,
+		},
+		{
+			name:     "htmx request with a missing target renders the page",
+			headers:  map[string]string{"HX-Request": "true", "HX-Target": "gone"},
+			expected: `<html><body><h1>Todos</h1><ul id="list"><li>one</li></ul><div id="count">1</div><p id="status">ok</p></body></html>`,
+		},
+		{
+			name:     "Target with a computed id",
+			headers:  map[string]string{"HX-Request": "true", "HX-Target": "status"},
+			expected: `ok`,
+		},
+		{
+			name:     "Boosted request renders the page",
+			headers:  map[string]string{"HX-Request": "true", "HX-Boosted": "true"},
+			fragment: "list",
+			expected: `<html><body><h1>Todos</h1><ul id="list"><li>one</li></ul><div id="count">1</div><p id="status">ok</p></body></html>`,
+		},
+		{
+			name:     "Missing fragment",
+			headers:  map[string]string{"HX-Request": "true"},
+			fragment: "missing",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/todos", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			err := Render(w, r, page(), tt.fragment)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Render() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && w.Body.String() != tt.expected {
+				t.Errorf("Render() = %q, want %q", w.Body.String(), tt.expected)
+			}
+			if w.Header().Get("Vary") != "HX-Request" {
+				t.Errorf("Vary header = %q, want HX-Request", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+// attrValue is an id computed at render time.
+type attrValue string
+
+func (me attrValue) AttrValue() (string, error) {
+	return string(me), nil
+}
@@ -0,0 +1,61 @@
+package htmx
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/assaidy/g"
+)
+
+// Render writes page to w, or only part of it when r is an htmx request.
+//
+// For htmx requests (other than boosted ones, which expect a full page),
+// only the element whose id is fragment is rendered, and Render returns an
+// error when page has no such element. When fragment is empty, the children
+// of the request target (HX-Target header) are rendered, since htmx swaps
+// the inner HTML of the target by default. The full page is rendered when
+// the target has no id or isn't in page.
+//
+// Example:
+//
+//	err := htmx.Render(w, r, todoPage(todos), "todo-list")
+func Render(w http.ResponseWriter, r *http.Request, page g.Node, fragment string) error {
+	w.Header().Add("Vary", "HX-Request")
+
+	node := page
+	if IsRequest(r) && !IsBoosted(r) {
+		switch target := RequestTarget(r); {
+		case fragment != "":
+			found := g.FindByID(page, fragment)
+			if found == nil {
+				return fmt.Errorf("htmx: no element with id '%s' in page", fragment)
+			}
+			node = found
+		case target != "":
+			if found := g.FindByID(page, target); found != nil {
+				node = &g.Element{Children: found.Children}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	return g.Render(w, node, g.InDocument(page))
+}
+
+// Handler returns a handler that builds the page for each request and
+// renders it with Render.
+//
+// Example:
+//
+//	mux.Handle("/todos", htmx.Handler("todo-list", func(r *http.Request) g.Node {
+//		return todoPage(loadTodos(r))
+//	}))
+func Handler(fragment string, page func(r *http.Request) g.Node) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := Render(w, r, page(r), fragment); err != nil {
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			log.Printf("couldn't render html: %v", err)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package g
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ID is an element id generated at render time. See UniqueID.
+type ID struct {
+	prefix string
+}
+
+// UniqueID returns an id that is resolved at render time into a value that
+// is unique in the rendered document: prefix followed by a number, such as
+// "email-1". Literal ids of the document are never reused.
+//
+// Use the same *ID as the value of other attributes to refer to the element,
+// e.g., in the for attribute of a Label. The numbers are assigned in
+// document order, so a tree always renders the same way.
+//
+// Example:
+//
+//	func Field(label string) Node {
+//		id := UniqueID("field")
+//		return Empty(
+//			Label(KV{"for": id}, Text(label)),
+//			Input(KV{"id": id}),
+//		)
+//	}
+//	// Field("Name") twice renders ids field-1 and field-2
+func UniqueID(prefix string) *ID {
+	return &ID{prefix: prefix}
+}
+
+func (me *ID) String() string {
+	return fmt.Sprintf("UniqueID(%q)", me.prefix)
+}
+
+// InDocument renders the node as a part of root, the full document, so that
+// its UniqueIDs get the values they have when root is rendered.
+//
+// Example:
+//
+//	err := Render(w, FindByID(page, "list"), InDocument(page))
+func InDocument(root Node) RenderOption {
+	return func(r *renderer) {
+		r.root = root
+	}
+}
+
+// idGenerator resolves the UniqueIDs of a document.
+type idGenerator struct {
+	used  map[string]bool
+	next  map[string]int // next number to try, by prefix
+	names map[*ID]string
+}
+
+// newIDGenerator resolves the UniqueIDs of root in document order, skipping
+// the literal ids found in root.
+func newIDGenerator(root Node) *idGenerator {
+	gen := &idGenerator{used: map[string]bool{}, next: map[string]int{}, names: map[*ID]string{}}
+	var ids []*ID
+	walkElements(root, func(e *Element) {
+		if id, ok := e.Attrs["id"].(string); ok {
+			gen.used[strings.TrimSpace(id)] = true
+		}
+		var keys []string
+		for key, value := range e.Attrs {
+			if _, ok := value.(*ID); ok {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			ids = append(ids, e.Attrs[key].(*ID))
+		}
+	})
+	for _, id := range ids {
+		gen.name(id)
+	}
+	return gen
+}
+
+// name returns the generated id of id, generating it the first time.
+func (me *idGenerator) name(id *ID) string {
+	if name, ok := me.names[id]; ok {
+		return name
+	}
+	for {
+		me.next[id.prefix]++
+		name := fmt.Sprintf("%s-%d", id.prefix, me.next[id.prefix])
+		if !me.used[name] {
+			me.used[name] = true
+			me.names[id] = name
+			return name
+		}
+	}
+}
+
+// walkElements calls fn for every element of node, in document order.
+func walkElements(node Node, fn func(*Element)) {
+	Walk(node, func(node Node) bool {
+		if e, ok := node.(*Element); ok {
+			fn(e)
+		}
+		return true
+	})
+}
@@ -0,0 +1,101 @@
+package g
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func field(label string) Node {
+	id := UniqueID("field")
+	return Empty(
+		Label(KV{"for": id}, Text(label)),
+		Input(KV{"id": id, "aria-describedby": UniqueID("hint")}),
+	)
+}
+
+func TestUniqueID(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     Node
+		expected string
+	}{
+		{
+			name: "Same component twice",
+			node: Form(field("A"), field("B")),
+			expected: `<form><label for="field-1">A</label><input aria-describedby="hint-1" id="field-1">` +
+				`<label for="field-2">B</label><input aria-describedby="hint-2" id="field-2"></form>`,
+		},
+		{
+			name:     "Literal ids are skipped",
+			node:     Div(P(KV{"id": UniqueID("x")}), P(KV{"id": "x-1"}), P(KV{"id": UniqueID("x")})),
+			expected: `<div><p id="x-2"></p><p id="x-1"></p><p id="x-3"></p></div>`,
+		},
+		{
+			name: "Reference before the element",
+			node: func() Node {
+				id := UniqueID("title")
+				return Section(KV{"aria-labelledby": id}, H2(KV{"id": id}))
+			}(),
+			expected: `<section aria-labelledby="title-1"><h2 id="title-1"></h2></section>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tt.node); err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Render() = %q, want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestUniqueID_Fragment(t *testing.T) {
+	page := Div(field("A"), Fragment("second", field("B")))
+	var buf bytes.Buffer
+	if err := RenderFragment(&buf, page, "second"); err != nil {
+		t.Fatalf("RenderFragment() error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `id="field-2"`) {
+		t.Errorf("RenderFragment() = %q, want the ids the fragment has in the page", buf.String())
+	}
+}
+
+func TestUniqueID_Diff(t *testing.T) {
+	patches := Diff(Div(field("A")), Div(field("A"), field("B")))
+	data, err := json.Marshal(patches)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	if got := string(data); !strings.Contains(got, `for=\"field-2\"`) || strings.Contains(got, `"set-attr"`) {
+		t.Errorf("Diff() = %s, want only inserts of the elements with field-2", got)
+	}
+}
+
+func TestValidate_DuplicateIDs(t *testing.T) {
+	id := UniqueID("x")
+	node := Div(P(KV{"id": "a"}), P(KV{"id": " a "}), P(KV{"id": id}), P(KV{"id": id}), Label(KV{"for": id}))
+
+	var result []string
+	for _, issue := range Validate(node) {
+		result = append(result, issue.String())
+	}
+	expected := []string{
+		`error at [0 1]: id "a" is used more than once`,
+		`error at [0 3]: UniqueID("x") is the id of more than one element`,
+	}
+	if strings.Join(result, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Validate() = %q, want %q", result, expected)
+	}
+
+	var verr *ValidationError
+	if err := Render(&bytes.Buffer{}, node, WithStrict()); !errors.As(err, &verr) {
+		t.Errorf("Render() error = %v, want a *ValidationError", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Package cssscan holds the CSS scanning shared by the css and email
+// packages: finding the end of statements, blocks and strings, and
+// rendering CSS into Style elements.
+package cssscan
+
+import (
+	"strings"
+)
+
+// Scan returns the index of the first of stops in s from i, outside of
+// strings, parentheses and brackets, or len(s).
+func Scan(s string, i int, stops string) int {
+	depth := 0
+	for ; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			i++
+		case c == '"' || c == '\'':
+			i = StringEnd(s, i)
+		case c == '(' || c == '[':
+			depth++
+		case (c == ')' || c == ']') && depth > 0:
+			depth--
+		case depth == 0 && strings.IndexByte(stops, c) >= 0:
+			return i
+		}
+	}
+	return len(s)
+}
+
+// BlockEnd returns the index of the } closing the block opened at i, or
+// len(s).
+func BlockEnd(s string, i int) int {
+	depth := 0
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"', '\'':
+			i = StringEnd(s, i)
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+// StringEnd returns the index of the quote closing the string opened at i.
+func StringEnd(s string, i int) int {
+	quote := s[i]
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote, '\n':
+			return i
+		}
+	}
+	return len(s)
+}
+
+// StripComments returns s without its comments.
+func StripComments(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '"' || s[i] == '\'':
+			end := min(StringEnd(s, i)+1, len(s))
+			sb.WriteString(s[i:end])
+			i = end
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return sb.String()
+			}
+			i += end + 4
+		default:
+			sb.WriteByte(s[i])
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// IsSpace reports whether c is CSS whitespace.
+func IsSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// Raw is CSS rendered as the content of a Style element. Unlike g.Text, it
+// doesn't escape the CSS, which browsers don't unescape in <style>; only
+// "</" is escaped, so the CSS can't close the element.
+type Raw string
+
+func (me Raw) Render() (string, error) {
+	return strings.ReplaceAll(string(me), "</", `<\/`), nil
+}
@@ -0,0 +1,72 @@
+package cssscan
+
+import (
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{name: "Stop", input: "a, b", expected: 1},
+		{name: "No stop", input: "a b", expected: 3},
+		{name: "In string", input: `[x=","], b`, expected: 7},
+		{name: "In parentheses", input: ":is(a, b), c", expected: 9},
+		{name: "Escaped", input: `a\,b, c`, expected: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Scan(tt.input, 0, ","); result != tt.expected {
+				t.Errorf("Scan(%q) = %d, want %d", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBlockEnd(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{input: "{ a }", expected: 4},
+		{input: "{ a { b } }", expected: 10},
+		{input: `{ content: "}" }`, expected: 15},
+		{input: "{ a", expected: 3},
+	}
+
+	for _, tt := range tests {
+		if result := BlockEnd(tt.input, 0); result != tt.expected {
+			t.Errorf("BlockEnd(%q) = %d, want %d", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestStripComments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "a /* b */ c", expected: "a  c"},
+		{input: `a { content: "/* b */" }`, expected: `a { content: "/* b */" }`},
+		{input: "a /* b", expected: "a "},
+	}
+
+	for _, tt := range tests {
+		if result := StripComments(tt.input); result != tt.expected {
+			t.Errorf("StripComments(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestRaw_Render(t *testing.T) {
+	result, err := Raw(`a > b { content: "</style>" }`).Render()
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	if expected := `a > b { content: "<\/style>" }`; result != expected {
+		t.Errorf("Render() = %q, want %q", result, expected)
+	}
+}
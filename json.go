@@ -0,0 +1,53 @@
+package g
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// JSONScript returns a Script of type application/json holding v marshaled
+// as JSON, to pass data to the scripts of the page. Unlike Text, the JSON
+// isn't escaped for HTML, which browsers don't unescape in <script>. It is
+// escaped for the script context instead: <, >, &, U+2028 and U+2029 are
+// written as \u escapes, so a value can't close the element. A marshal
+// error is returned by Render.
+//
+// Example:
+//
+//	JSONScript("initial-state", State{User: "</script>"})
+//	// <script id="initial-state" type="application/json">{"User":"\u003c/script\u003e"}</script>
+//
+//	// in JavaScript:
+//	// JSON.parse(document.getElementById("initial-state").textContent)
+//
+// https://developer.mozilla.org/en-US/docs/Web/HTML/Reference/Elements/script/type
+func JSONScript(id string, v any) *Element {
+	return Script(KV{"id": id, "type": "application/json"}, &jsonText{v: v})
+}
+
+// JSONLD returns a Script of type application/ld+json holding v marshaled
+// as JSON, for structured data such as schema.org. It escapes v like
+// JSONScript.
+//
+// Example:
+//
+//	JSONLD(map[string]any{"@context": "https://schema.org", "@type": "Article", "headline": title})
+//
+// https://developer.mozilla.org/en-US/docs/Web/HTML/Reference/Elements/script/type
+func JSONLD(v any) *Element {
+	return Script(KV{"type": "application/ld+json"}, &jsonText{v: v})
+}
+
+// jsonText renders v as JSON for the content of a script.
+type jsonText struct {
+	v any
+}
+
+func (me *jsonText) Render() (string, error) {
+	// json.Marshal escapes <, >, &, U+2028 and U+2029.
+	b, err := json.Marshal(me.v)
+	if err != nil {
+		return "", fmt.Errorf("marshaling JSON for <script>: %w", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,58 @@
+package g
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     Node
+		expected string
+		err      string
+	}{
+		{
+			name:     "Data",
+			node:     JSONScript("state", map[string]any{"user": "Ann  Lee", "n": 1}),
+			expected: `<script id="state" type="application/json">{"n":1,"user":"Ann  Lee"}</script>`,
+		},
+		{
+			name:     "Script context",
+			node:     JSONScript("state", []string{"</script><!--", "a & b", "\u2028\u2029"}),
+			expected: `<script id="state" type="application/json">["\u003c/script\u003e\u003c!--","a \u0026 b","\u2028\u2029"]</script>`,
+		},
+		{
+			name: "JSON-LD",
+			node: JSONLD(struct {
+				Context  string `json:"@context"`
+				Type     string `json:"@type"`
+				Headline string `json:"headline"`
+			}{"https://schema.org", "Article", `"Quotes" <b>`}),
+			expected: `<script type="application/ld+json">{"@context":"https://schema.org","@type":"Article","headline":"\"Quotes\" \u003cb\u003e"}</script>`,
+		},
+		{
+			name: "Marshal error",
+			node: Body(JSONLD(map[string]any{"f": func() {}})),
+			err:  "marshaling JSON for <script>: json: unsupported type: func()",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.node.Render()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Render() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Render() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Client of github.com/assaidy/g/live: connects every [data-g-live] container
+// to its handler, forwards events and applies the patches it receives.
+(() => {
+  "use strict";
+
+  function parse(html) {
+    const template = document.createElement("template");
+    template.innerHTML = html;
+    return template.content.firstChild || document.createTextNode("");
+  }
+
+  function apply(root, patch) {
+    const path = patch.path;
+    let parent = root;
+    for (let i = 0; i < path.length - 1; i++) {
+      parent = parent.childNodes[path[i]];
+    }
+    const index = path[path.length - 1];
+    const node = parent.childNodes[index];
+
+    switch (patch.op) {
+      case "replace":
+        parent.replaceChild(parse(patch.html), node);
+        break;
+      case "insert":
+        parent.insertBefore(parse(patch.html), node || null);
+        break;
+      case "remove":
+        parent.removeChild(node);
+        break;
+      case "move":
+        parent.removeChild(node);
+        parent.insertBefore(node, parent.childNodes[patch.to || 0] || null);
+        break;
+      case "set-text":
+        node.nodeValue = patch.value || "";
+        break;
+      case "set-attr":
+        node.setAttribute(patch.name, patch.value || "");
+        if (patch.name === "value" && "value" in node) node.value = patch.value || "";
+        if (patch.name === "checked" && "checked" in node) node.checked = true;
+        break;
+      case "remove-attr":
+        node.removeAttribute(patch.name);
+        if (patch.name === "checked" && "checked" in node) node.checked = false;
+        break;
+      default:
+        console.error("g/live: unknown patch", patch);
+    }
+  }
+
+  function connect(root) {
+    const url = new URL(root.dataset.gLive, location.href);
+    url.protocol = url.protocol === "https:" ? "wss:" : "ws:";
+    const socket = new WebSocket(url);
+
+    const send = (event) => {
+      if (socket.readyState === WebSocket.OPEN) socket.send(JSON.stringify(event));
+    };
+
+    socket.addEventListener("message", (e) => {
+      const message = JSON.parse(e.data);
+      if (message.error) console.error("g/live:", message.error);
+      if (message.init !== undefined) root.innerHTML = message.init;
+      for (const patch of message.patches || []) apply(root, patch);
+    });
+
+    root.addEventListener("click", (e) => {
+      const target = e.target.closest("[data-g-click]");
+      if (!target || !root.contains(target)) return;
+      e.preventDefault();
+      send({ type: "click", name: target.dataset.gClick, value: target.dataset.gValue || "" });
+    });
+
+    root.addEventListener("submit", (e) => {
+      const form = e.target.closest("[data-g-submit]");
+      if (!form || !root.contains(form)) return;
+      e.preventDefault();
+      const fields = {};
+      for (const [key, value] of new FormData(form)) {
+        (fields[key] ||= []).push(String(value));
+      }
+      send({ type: "submit", name: form.dataset.gSubmit, form: fields });
+    });
+  }
+
+  document.querySelectorAll("[data-g-live]").forEach(connect);
+})();
@@ -0,0 +1,187 @@
+// Package live renders server-driven views that update in the browser over
+// a WebSocket.
+//
+// A View holds the state of one client. The browser sends the events
+// declared with the data-g-click and data-g-submit attributes, the View
+// handles them, and the Handler pushes the DOM patches (computed by g.Diff)
+// between the previous and the next render.
+package live
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/assaidy/g"
+)
+
+//go:embed client.js
+var clientJS []byte
+
+// scriptParam is the query parameter that makes a Handler serve the client
+// script instead of the page.
+const scriptParam = "g-live"
+
+// View is the server side state of a live page, one per connected client.
+type View interface {
+	// Render builds the content of the page from the current state. It must
+	// return a fresh tree on every call.
+	Render() g.Node
+	// HandleEvent is called for every event sent by the browser. The view is
+	// rendered again and patched in the browser once it returns.
+	HandleEvent(event Event) error
+}
+
+// Event is an interaction reported by the browser.
+//
+// Clicking an element with data-g-click="name" sends an Event with Type
+// "click" and Name "name", and Value taken from its data-g-value attribute.
+// Submitting a form with data-g-submit="name" sends an Event with Type
+// "submit" and the form fields in Form.
+type Event struct {
+	Type  string     `json:"type"`
+	Name  string     `json:"name"`
+	Value string     `json:"value,omitempty"`
+	Form  url.Values `json:"form,omitempty"`
+}
+
+// Handler serves a live view.
+//
+// A plain GET renders the page, a WebSocket request connects a client, and
+// GET with the query "?g-live=client.js" serves the embedded client script.
+//
+// Example:
+//
+//	mux.Handle("/counter", &live.Handler{
+//		Mount: func(r *http.Request) live.View { return &counter{} },
+//	})
+type Handler struct {
+	// Mount creates the view of a new client. It is called once for the
+	// initial page render and once for each WebSocket connection, whose
+	// request has the path and query of the page.
+	Mount func(r *http.Request) View
+	// Layout wraps the live content in a page. When nil, a minimal HTML
+	// document is used.
+	Layout func(content g.Node) g.Node
+	// ErrorLog logs failed connections and event handlers. When nil, the
+	// standard logger is used.
+	ErrorLog *log.Logger
+}
+
+// message is what the server sends to the client. Init replaces the whole
+// content, Patches are applied to it and Error is reported in the console.
+type message struct {
+	Init    *string   `json:"init,omitempty"`
+	Patches []g.Patch `json:"patches,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+func (me *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case isWebSocketRequest(r):
+		me.serveSocket(w, r)
+	case r.URL.Query().Get(scriptParam) == "client.js":
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		w.Write(clientJS)
+	default:
+		me.servePage(w, r)
+	}
+}
+
+func (me *Handler) servePage(w http.ResponseWriter, r *http.Request) {
+	scriptURL := url.URL{Path: r.URL.Path, RawQuery: scriptParam + "=client.js"}
+	content := g.Empty(
+		g.Div(g.KV{"data-g-live": r.URL.RequestURI()}, me.Mount(r).Render()),
+		g.Script(g.KV{"src": scriptURL.String(), "defer": true}),
+	)
+
+	var page g.Node
+	if me.Layout != nil {
+		page = me.Layout(content)
+	} else {
+		page = g.Html(
+			g.Head(g.Meta(g.KV{"charset": "UTF-8"})),
+			g.Body(content),
+		)
+	}
+
+	html, err := page.Render()
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		me.logf("couldn't render live page: %v", err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte("<!DOCTYPE html>" + html))
+}
+
+func (me *Handler) serveSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrade(w, r)
+	if err != nil {
+		me.logf("couldn't upgrade live connection: %v", err)
+		return
+	}
+	defer conn.close()
+
+	view := me.Mount(r)
+	prev := view.Render()
+	html, err := prev.Render()
+	if err != nil {
+		me.logf("couldn't render live view: %v", err)
+		conn.writeClose(1011)
+		return
+	}
+	if err := me.send(conn, message{Init: &html}); err != nil {
+		return
+	}
+
+	for {
+		data, err := conn.readMessage()
+		if err != nil {
+			return
+		}
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			me.logf("couldn't decode live event: %v", err)
+			continue
+		}
+
+		if err := view.HandleEvent(event); err != nil {
+			me.logf("live event %s %q failed: %v", event.Type, event.Name, err)
+			if err := me.send(conn, message{Error: err.Error()}); err != nil {
+				return
+			}
+			continue
+		}
+
+		next := view.Render()
+		patches := g.Diff(prev, next)
+		prev = next
+		if len(patches) == 0 {
+			continue
+		}
+		if err := me.send(conn, message{Patches: patches}); err != nil {
+			return
+		}
+	}
+}
+
+func (me *Handler) send(conn *wsConn, m message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		me.logf("couldn't encode live message: %v", err)
+		conn.writeClose(1011)
+		return err
+	}
+	return conn.writeMessage(data)
+}
+
+func (me *Handler) logf(format string, args ...any) {
+	if me.ErrorLog != nil {
+		me.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
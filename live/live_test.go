@@ -0,0 +1,351 @@
+package live
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/assaidy/g"
+)
+
+type counterView struct {
+	count int
+	name  string
+}
+
+func (me *counterView) Render() g.Node {
+	return g.Div(
+		g.Button(g.KV{"data-g-click": "inc"}, g.Text("+")),
+		g.Span(g.Text(strconv.Itoa(me.count))),
+		g.Form(g.KV{"data-g-submit": "rename"},
+			g.Input(g.KV{"name": "name", "value": me.name}),
+		),
+	)
+}
+
+func (me *counterView) HandleEvent(event Event) error {
+	switch {
+	case event.Type == "click" && event.Name == "inc":
+		me.count++
+	case event.Type == "submit" && event.Name == "rename":
+		me.name = event.Form.Get("name")
+	default:
+		return fmt.Errorf("unknown event %q", event.Name)
+	}
+	return nil
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(&Handler{
+		Mount:    func(r *http.Request) View { return &counterView{} },
+		ErrorLog: log.New(io.Discard, "", 0),
+	})
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHandler_Page(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/counter")
+	if err != nil {
+		t.Fatalf("GET page error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	expected := []string{
+		`<!DOCTYPE html>`,
+		`<div data-g-live="/counter"><div><button data-g-click="inc">+</button><span>0</span>`,
+		`<script defer src="/counter?g-live=client.js"></script>`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(string(body), e) {
+			t.Errorf("page = %q, want it to contain %q", body, e)
+		}
+	}
+}
+
+func TestHandler_PageQuery(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/counter?tab=2&sort=name")
+	if err != nil {
+		t.Fatalf("GET page error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	want := `<div data-g-live="/counter?tab=2&amp;sort=name">`
+	if !strings.Contains(string(body), want) {
+		t.Errorf("page = %q, want it to contain %q", body, want)
+	}
+}
+
+func TestHandler_Script(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/counter?g-live=client.js")
+	if err != nil {
+		t.Fatalf("GET script error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/javascript") {
+		t.Errorf("Content-Type = %q, want text/javascript", ct)
+	}
+	if !strings.Contains(string(body), "data-g-live") {
+		t.Errorf("script doesn't look like the live client: %q", body)
+	}
+}
+
+func TestHandler_Events(t *testing.T) {
+	server := newTestServer(t)
+	client := dialTestSocket(t, server)
+
+	var init message
+	client.readJSON(&init)
+	if init.Init == nil || !strings.Contains(*init.Init, "<span>0</span>") {
+		t.Fatalf("init message = %+v, want the rendered view", init)
+	}
+
+	tests := []struct {
+		name     string
+		event    Event
+		expected string
+	}{
+		{
+			name:     "Click",
+			event:    Event{Type: "click", Name: "inc"},
+			expected: `{"patches":[{"op":"set-text","path":[0,1,0],"value":"1"}]}`,
+		},
+		{
+			name:     "Submit",
+			event:    Event{Type: "submit", Name: "rename", Form: map[string][]string{"name": {"bob"}}},
+			expected: `{"patches":[{"op":"set-attr","path":[0,2,0],"name":"value","value":"bob"}]}`,
+		},
+		{
+			name:     "Failing handler",
+			event:    Event{Type: "click", Name: "unknown"},
+			expected: `{"error":"unknown event \"unknown\""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client.writeJSON(tt.event)
+			result := client.read()
+			if string(result) != tt.expected {
+				t.Errorf("message = %s, want %s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHandler_ProtocolErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames func(client *testSocket)
+	}{
+		{
+			name: "Control frame too big",
+			frames: func(client *testSocket) {
+				client.writeFrame(true, opPing, make([]byte, 126))
+			},
+		},
+		{
+			name: "Fragmented control frame",
+			frames: func(client *testSocket) {
+				client.writeFrame(false, opPing, []byte("a"))
+			},
+		},
+		{
+			name: "Continuation without a message",
+			frames: func(client *testSocket) {
+				client.writeFrame(true, opContinuation, []byte("{}"))
+			},
+		},
+		{
+			name: "New message inside a fragmented one",
+			frames: func(client *testSocket) {
+				client.writeFrame(false, opText, []byte(`{"type":`))
+				client.writeFrame(true, opText, []byte("{}"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dialTestSocket(t, newTestServer(t))
+			client.read() // init
+			tt.frames(client)
+			opcode, payload := client.readFrame()
+			if opcode != opClose || len(payload) < 2 || binary.BigEndian.Uint16(payload) != 1002 {
+				t.Errorf("read frame %d %v, want a close frame with code 1002", opcode, payload)
+			}
+		})
+	}
+}
+
+func TestHandler_FragmentedMessage(t *testing.T) {
+	client := dialTestSocket(t, newTestServer(t))
+	client.read() // init
+
+	client.writeFrame(false, opText, []byte(`{"type":"click",`))
+	client.writeFrame(true, opPing, []byte("ping"))
+	client.writeFrame(true, opContinuation, []byte(`"name":"inc"}`))
+	if opcode, payload := client.readFrame(); opcode != opPong || string(payload) != "ping" {
+		t.Fatalf("read frame %d %q, want the pong", opcode, payload)
+	}
+	want := `{"patches":[{"op":"set-text","path":[0,1,0],"value":"1"}]}`
+	if result := client.read(); string(result) != want {
+		t.Errorf("message = %s, want %s", result, want)
+	}
+}
+
+func TestHandler_CrossOrigin(t *testing.T) {
+	server := newTestServer(t)
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	defer conn.Close()
+	writeHandshake(conn, server.Listener.Addr().String(), "http://evil.example")
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("read handshake response error: %v", err)
+	}
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+}
+
+// testSocket is a minimal WebSocket client used to talk to a Handler.
+type testSocket struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialTestSocket(t *testing.T, server *httptest.Server) *testSocket {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	writeHandshake(conn, server.Listener.Addr().String(), "")
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("read handshake response error: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status = %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+
+	return &testSocket{t: t, conn: conn, reader: reader}
+}
+
+func writeHandshake(w io.Writer, host, origin string) {
+	key := make([]byte, 16)
+	rand.Read(key)
+	fmt.Fprintf(w, "GET /counter HTTP/1.1\r\nHost: %s\r\n", host)
+	fmt.Fprint(w, "Connection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13\r\n")
+	fmt.Fprintf(w, "Sec-WebSocket-Key: %s\r\n", base64.StdEncoding.EncodeToString(key))
+	if origin != "" {
+		fmt.Fprintf(w, "Origin: %s\r\n", origin)
+	}
+	fmt.Fprint(w, "\r\n")
+}
+
+func (me *testSocket) writeJSON(v any) {
+	me.t.Helper()
+	payload, err := json.Marshal(v)
+	if err != nil {
+		me.t.Fatalf("marshal error: %v", err)
+	}
+	me.writeFrame(true, opText, payload)
+}
+
+func (me *testSocket) writeFrame(fin bool, opcode byte, payload []byte) {
+	me.t.Helper()
+	frame := []byte{opcode}
+	if fin {
+		frame[0] |= 0x80
+	}
+	if len(payload) < 126 {
+		frame = append(frame, 0x80|byte(len(payload)))
+	} else {
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := me.conn.Write(frame); err != nil {
+		me.t.Fatalf("write error: %v", err)
+	}
+}
+
+// read returns the payload of the next frame, which must be a text frame.
+func (me *testSocket) read() []byte {
+	me.t.Helper()
+	opcode, payload := me.readFrame()
+	if opcode != opText {
+		me.t.Fatalf("read opcode %d, want text", opcode)
+	}
+	return payload
+}
+
+func (me *testSocket) readFrame() (byte, []byte) {
+	me.t.Helper()
+	var header [2]byte
+	if _, err := io.ReadFull(me.reader, header[:]); err != nil {
+		me.t.Fatalf("read error: %v", err)
+	}
+	length := int(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(me.reader, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(me.reader, ext[:])
+		length = int(binary.BigEndian.Uint64(ext[:]))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(me.reader, payload); err != nil {
+		me.t.Fatalf("read error: %v", err)
+	}
+	return header[0] & 0x0F, payload
+}
+
+func (me *testSocket) readJSON(v any) {
+	me.t.Helper()
+	if err := json.Unmarshal(me.read(), v); err != nil {
+		me.t.Fatalf("unmarshal error: %v", err)
+	}
+}
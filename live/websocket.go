@@ -0,0 +1,249 @@
+package live
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// websocketGUID is the magic value from RFC 6455 used to compute the
+// Sec-WebSocket-Accept header.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxMessageSize limits the size of a message received from a client.
+const maxMessageSize = 1 << 20
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// wsConn is a minimal server side WebSocket connection (RFC 6455). It only
+// supports what the live client needs: text messages, ping/pong and close.
+type wsConn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// isWebSocketRequest reports whether r asks for a WebSocket upgrade.
+func isWebSocketRequest(r *http.Request) bool {
+	return headerContains(r.Header, "Connection", "upgrade") &&
+		headerContains(r.Header, "Upgrade", "websocket")
+}
+
+// upgrade performs the WebSocket handshake and takes over the connection.
+// On failure an HTTP error has already been written to w.
+func upgrade(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, fmt.Errorf("websocket upgrade with method %s", r.Method)
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("unsupported websocket version %q", r.Header.Get("Sec-WebSocket-Version"))
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing websocket key", http.StatusBadRequest)
+		return nil, errors.New("missing Sec-WebSocket-Key header")
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin websocket request", http.StatusForbidden)
+		return nil, fmt.Errorf("cross-origin websocket request from %q", r.Header.Get("Origin"))
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("response writer does not support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't hijack connection: %w", err)
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	fmt.Fprint(rw, "HTTP/1.1 101 Switching Protocols\r\n")
+	fmt.Fprint(rw, "Upgrade: websocket\r\n")
+	fmt.Fprint(rw, "Connection: Upgrade\r\n")
+	fmt.Fprintf(rw, "Sec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(sum[:]))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("couldn't write handshake: %w", err)
+	}
+
+	return &wsConn{conn: conn, reader: rw.Reader}, nil
+}
+
+// readMessage returns the payload of the next text or binary message.
+// Control frames are handled on the way. It returns io.EOF once the client
+// closes the connection.
+func (me *wsConn) readMessage() ([]byte, error) {
+	var message []byte
+	fragmented := false // whether a message was started by a frame without fin
+	for {
+		fin, opcode, payload, err := me.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		// Control frames can't be fragmented and carry at most 125 bytes
+		// (RFC 6455, section 5.5).
+		if opcode >= opClose && (!fin || len(payload) > 125) {
+			me.writeClose(1002)
+			return nil, fmt.Errorf("invalid websocket control frame with opcode %d", opcode)
+		}
+
+		switch opcode {
+		case opText, opBinary, opContinuation:
+			// Only continuation frames may follow the first frame of a
+			// fragmented message (RFC 6455, section 5.4).
+			if (opcode == opContinuation) != fragmented {
+				me.writeClose(1002)
+				if fragmented {
+					return nil, errors.New("new websocket message in the middle of a fragmented one")
+				}
+				return nil, errors.New("websocket continuation frame outside of a fragmented message")
+			}
+			fragmented = !fin
+			if len(message)+len(payload) > maxMessageSize {
+				me.writeClose(1009)
+				return nil, errors.New("websocket message too big")
+			}
+			message = append(message, payload...)
+			if fin {
+				return message, nil
+			}
+		case opPing:
+			if err := me.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong:
+		case opClose:
+			me.writeClose(1000)
+			return nil, io.EOF
+		default:
+			me.writeClose(1002)
+			return nil, fmt.Errorf("unknown websocket opcode %d", opcode)
+		}
+	}
+}
+
+func (me *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(me.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	if header[1]&0x80 == 0 {
+		return false, 0, nil, errors.New("client websocket frame is not masked")
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(me.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(me.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxMessageSize {
+		me.writeClose(1009)
+		return false, 0, nil, errors.New("websocket frame too big")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(me.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(me.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// writeMessage sends payload as a single text frame.
+func (me *wsConn) writeMessage(payload []byte) error {
+	return me.writeFrame(opText, payload)
+}
+
+func (me *wsConn) writeClose(code uint16) error {
+	return me.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, code))
+}
+
+func (me *wsConn) writeFrame(opcode byte, payload []byte) error {
+	me.writeMu.Lock()
+	defer me.writeMu.Unlock()
+
+	frame := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	frame = append(frame, payload...)
+
+	_, err := me.conn.Write(frame)
+	return err
+}
+
+func (me *wsConn) close() error {
+	return me.conn.Close()
+}
+
+// sameOrigin protects against cross-site WebSocket hijacking: browsers
+// always send Origin, and it has to match the host being connected to.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+// Package markdown converts between Markdown and g trees.
+//
+// Parse turns CommonMark, with the GitHub Flavored Markdown tables, task
+// lists, strikethrough and autolinks, into elements built with the g
+// constructors, which can be post-processed like any other tree:
+//
+//	doc := markdown.Parse(post.Body)
+//	for _, table := range selector.SelectParsed(doc, selector.MustParse("table")...) {
+//		table.Attrs = g.KV{"class": "table"}
+//	}
+//
+// Render goes the other way, e.g., to feed a page to tools that read
+// Markdown.
+package markdown
+
+import (
+	"bufio"
+	"bytes"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/assaidy/g"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	ghtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var md = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// Parse converts Markdown into a tagless element holding the blocks of the
+// document.
+//
+// Raw HTML in the source is dropped; link and image URLs go through the
+// URL checks of g when rendered.
+//
+// Example:
+//
+//	markdown.Parse("# Hello\n\nSome *text*.")
+//	// Empty(H1(Text("Hello")), P(Text("Some "), Em(Text("text")), Text(".")))
+func Parse(source string) *g.Element {
+	src := []byte(source)
+	doc := md.Parser().Parse(text.NewReader(src))
+	c := &converter{source: src}
+	return g.Empty(c.children(doc)...)
+}
+
+type converter struct {
+	source []byte
+}
+
+func (me *converter) children(n ast.Node) []any {
+	var result []any
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		result = append(result, me.convert(child)...)
+	}
+	return result
+}
+
+// convert returns the g nodes of n, as arguments for the g constructors.
+func (me *converter) convert(n ast.Node) []any {
+	switch n := n.(type) {
+	// blocks
+	case *ast.Paragraph:
+		return []any{g.P(me.children(n)...)}
+	case *ast.TextBlock: // paragraph of a tight list item
+		return me.children(n)
+	case *ast.Heading:
+		return []any{headings[n.Level-1](me.children(n)...)}
+	case *ast.ThematicBreak:
+		return []any{g.Hr()}
+	case *ast.Blockquote:
+		return []any{g.Blockquote(me.children(n)...)}
+	case *ast.CodeBlock:
+		return []any{g.Pre(g.Code(me.lines(n)))}
+	case *ast.FencedCodeBlock:
+		code := g.Code(me.lines(n))
+		if lang := n.Language(me.source); lang != nil {
+			code.Attrs = g.KV{"class": "language-" + string(lang)}
+		}
+		return []any{g.Pre(code)}
+	case *ast.List:
+		if !n.IsOrdered() {
+			return []any{g.Ul(me.children(n)...)}
+		}
+		ol := g.Ol(me.children(n)...)
+		if n.Start != 1 {
+			ol.Attrs = g.KV{"start": strconv.Itoa(n.Start)}
+		}
+		return []any{ol}
+	case *ast.ListItem:
+		return []any{g.Li(me.children(n)...)}
+	case *ast.HTMLBlock, *ast.RawHTML:
+		return nil
+
+	// GFM blocks
+	case *east.Table:
+		return []any{me.table(n)}
+
+	// inlines
+	case *ast.Text:
+		value := n.Segment.Value(me.source)
+		result := []any{g.Text(me.decode(value))}
+		if n.IsRaw() {
+			result = []any{g.Text(value)}
+		}
+		switch {
+		case n.HardLineBreak():
+			result = append(result, g.Br())
+		case n.SoftLineBreak():
+			result = append(result, g.Text("\n"))
+		}
+		return result
+	case *ast.String:
+		if n.IsCode() || n.IsRaw() {
+			return []any{g.Text(n.Value)}
+		}
+		return []any{g.Text(me.decode(n.Value))}
+	case *ast.CodeSpan:
+		var sb strings.Builder
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			if t, ok := c.(*ast.Text); ok {
+				sb.Write(t.Segment.Value(me.source))
+			}
+		}
+		return []any{g.Code(g.Text(strings.ReplaceAll(sb.String(), "\n", " ")))}
+	case *ast.Emphasis:
+		if n.Level >= 2 {
+			return []any{g.Strong(me.children(n)...)}
+		}
+		return []any{g.Em(me.children(n)...)}
+	case *ast.Link:
+		attrs := g.KV{"href": string(util.URLEscape(n.Destination, true))}
+		if n.Title != nil {
+			attrs["title"] = me.decode(n.Title)
+		}
+		return []any{g.A(append([]any{attrs}, me.children(n)...)...)}
+	case *ast.AutoLink:
+		url := string(util.URLEscape(n.URL(me.source), false))
+		if n.AutoLinkType == ast.AutoLinkEmail && !strings.HasPrefix(strings.ToLower(url), "mailto:") {
+			url = "mailto:" + url
+		}
+		return []any{g.A(g.KV{"href": url}, g.Text(n.Label(me.source)))}
+	case *ast.Image:
+		attrs := g.KV{"src": string(util.URLEscape(n.Destination, true)), "alt": me.plainText(n)}
+		if n.Title != nil {
+			attrs["title"] = me.decode(n.Title)
+		}
+		return []any{g.Img(attrs)}
+
+	// GFM inlines
+	case *east.Strikethrough:
+		return []any{g.Del(me.children(n)...)}
+	case *east.TaskCheckBox:
+		return []any{g.Input(g.KV{"type": "checkbox", "checked": n.IsChecked, "disabled": true}), g.Text(" ")}
+
+	default:
+		return me.children(n)
+	}
+}
+
+var headings = []func(...any) *g.Element{g.H1, g.H2, g.H3, g.H4, g.H5, g.H6}
+
+func (me *converter) table(n *east.Table) *g.Element {
+	table := g.Table()
+	var body []any
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		_, isHeader := row.(*east.TableHeader)
+		var cells []any
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			newCell := g.Td
+			if isHeader {
+				newCell = g.Th
+			}
+			e := newCell(me.children(cell)...)
+			if align := cell.(*east.TableCell).Alignment; align != east.AlignNone {
+				e.Attrs = g.KV{"style": "text-align: " + align.String()}
+			}
+			cells = append(cells, e)
+		}
+		if isHeader {
+			table.Children = append(table.Children, g.Thead(g.Tr(cells...)))
+		} else {
+			body = append(body, g.Tr(cells...))
+		}
+	}
+	if len(body) > 0 {
+		table.Children = append(table.Children, g.Tbody(body...))
+	}
+	return table
+}
+
+// lines returns the content of a code block.
+func (me *converter) lines(n ast.Node) g.CodeText {
+	var sb strings.Builder
+	lines := n.Lines()
+	for i := range lines.Len() {
+		line := lines.At(i)
+		sb.Write(line.Value(me.source))
+	}
+	return g.CodeText(sb.String())
+}
+
+// plainText returns the text of the children of n, e.g., the alt text of
+// an image.
+func (me *converter) plainText(n ast.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			sb.WriteString(me.decode(c.Segment.Value(me.source)))
+		case *ast.String:
+			sb.Write(c.Value)
+		default:
+			sb.WriteString(me.plainText(c))
+		}
+	}
+	return sb.String()
+}
+
+// decode resolves the backslash escapes and character references of
+// Markdown text, the way the goldmark HTML renderer does.
+func (me *converter) decode(value []byte) string {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	ghtml.DefaultWriter.Write(w, value)
+	w.Flush()
+	return html.UnescapeString(buf.String())
+}
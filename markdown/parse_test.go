@@ -0,0 +1,102 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Headings and paragraphs",
+			input:    "# Title\n\nSome *emphasis*, **strong** and `code`.\n\n### Sub",
+			expected: `<h1>Title</h1><p>Some <em>emphasis</em>, <strong>strong</strong> and <code>code</code>.</p><h3>Sub</h3>`,
+		},
+		{
+			name:     "Soft and hard line breaks",
+			input:    "a\nb  \nc",
+			expected: `<p>a b<br>c</p>`,
+		},
+		{
+			name:     "Escapes and entities",
+			input:    `\*not emphasis\* &amp; &lt;b&gt; &copy;`,
+			expected: `<p>*not emphasis* &amp; &lt;b&gt; ©</p>`,
+		},
+		{
+			name:     "Links and images",
+			input:    `[home](/ "Home page") ![a *logo*](/logo.png) <https://example.com> www.example.org`,
+			expected: `<p><a href="/" title="Home page">home</a> <img alt="a logo" src="/logo.png"> <a href="https://example.com">https://example.com</a> <a href="http://www.example.org">www.example.org</a></p>`,
+		},
+		{
+			name:     "Unsafe link",
+			input:    `[x](javascript:alert(1))`,
+			expected: `<p><a href="about:invalid#g-unsafe-url">x</a></p>`,
+		},
+		{
+			name:     "Lists",
+			input:    "- a\n- b\n\n3. c\n4. d\n",
+			expected: `<ul><li>a</li><li>b</li></ul><ol start="3"><li>c</li><li>d</li></ol>`,
+		},
+		{
+			name:     "Loose list",
+			input:    "- a\n\n- b\n",
+			expected: `<ul><li><p>a</p></li><li><p>b</p></li></ul>`,
+		},
+		{
+			name:     "Task list",
+			input:    "- [x] done\n- [ ] todo\n",
+			expected: `<ul><li><input checked disabled type="checkbox"> done</li><li><input disabled type="checkbox"> todo</li></ul>`,
+		},
+		{
+			name:     "Code blocks keep whitespace",
+			input:    "```go\nif x {\n\treturn \"<a>\"\n}\n```\n\n    indented\n      code\n",
+			expected: "<pre><code class=\"language-go\">if x {\n\treturn &#34;&lt;a&gt;&#34;\n}\n</code></pre><pre><code>indented\n  code\n</code></pre>",
+		},
+		{
+			name:     "Blockquote and thematic break",
+			input:    "> quoted\n\n---\n",
+			expected: `<blockquote><p>quoted</p></blockquote><hr>`,
+		},
+		{
+			name:     "Strikethrough",
+			input:    "~~old~~ new",
+			expected: `<p><del>old</del> new</p>`,
+		},
+		{
+			name:  "Table",
+			input: "| a | b |\n|:--|--:|\n| 1 | 2 |\n| 3 | 4 |\n",
+			expected: `<table><thead><tr><th style="text-align: left">a</th><th style="text-align: right">b</th></tr></thead>` +
+				`<tbody><tr><td style="text-align: left">1</td><td style="text-align: right">2</td></tr>` +
+				`<tr><td style="text-align: left">3</td><td style="text-align: right">4</td></tr></tbody></table>`,
+		},
+		{
+			name:     "Raw HTML is dropped",
+			input:    "<div onclick=\"x()\">block</div>\n\ninline <b>bold</b>",
+			expected: `<p>inline bold</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(tt.input).Render()
+			if err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Parse(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParse_Valid(t *testing.T) {
+	doc := Parse("# T\n\n- [ ] a\n- b\n\n| x |\n|---|\n| y |\n\n> q\n")
+	for _, issue := range g.Validate(doc) {
+		t.Errorf("Validate() issue: %s", issue)
+	}
+}
@@ -0,0 +1,513 @@
+package markdown
+
+import (
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/assaidy/g"
+	"golang.org/x/net/html"
+)
+
+// Render writes node to w as Markdown.
+//
+// Headings, paragraphs, lists (including task lists), links, images,
+// emphasis, strikethrough, code, tables, blockquotes, line breaks and
+// thematic breaks map to their Markdown syntax. Grouping elements such as
+// Div, Section and Body only contribute their content, and Head, Script,
+// Style and Template are left out. Anything else is written as inline
+// HTML, as is a table that GFM can't express (no header row, or cells
+// holding blocks).
+//
+// Example:
+//
+//	err := markdown.Render(os.Stdout, g.Empty(g.H1(g.Text("Hi")), g.P(g.Em(g.Text("there")))))
+//	// # Hi
+//	//
+//	// *there*
+func Render(w io.Writer, node g.Node) error {
+	r := &renderer{}
+	s := r.blocks([]g.Node{node})
+	if r.err != nil {
+		return r.err
+	}
+	if s != "" {
+		s += "\n"
+	}
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+type renderer struct {
+	err error // the first render error
+}
+
+// blockTags are the elements rendered as blocks, the others being inline.
+var blockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "body": true,
+	"details": true, "dialog": true, "div": true, "dl": true, "fieldset": true,
+	"figure": true, "footer": true, "form": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "head": true, "header": true, "hgroup": true,
+	"hr": true, "html": true, "main": true, "menu": true, "nav": true, "ol": true,
+	"p": true, "pre": true, "script": true, "search": true, "section": true,
+	"style": true, "table": true, "template": true, "ul": true,
+}
+
+// containerTags only group their content, which is rendered in their place.
+var containerTags = map[string]bool{
+	"article": true, "aside": true, "body": true, "div": true, "footer": true,
+	"header": true, "hgroup": true, "html": true, "main": true, "nav": true,
+	"search": true, "section": true,
+}
+
+// skippedTags have no content for readers.
+var skippedTags = map[string]bool{
+	"head": true, "script": true, "style": true, "template": true,
+}
+
+func tagOf(node g.Node) string {
+	if e, ok := node.(*g.Element); ok {
+		return strings.ToLower(e.Tag)
+	}
+	return ""
+}
+
+// blocks renders nodes as blocks separated by blank lines.
+func (me *renderer) blocks(nodes []g.Node) string {
+	return strings.Join(me.blockParts(nodes), "\n\n")
+}
+
+// blockParts renders nodes as blocks. Runs of inline nodes make a
+// paragraph.
+func (me *renderer) blockParts(nodes []g.Node) []string {
+	var parts []string
+	var inline []g.Node
+	flush := func() {
+		if s := strings.TrimSpace(me.inlines(inline)); s != "" {
+			parts = append(parts, escapeLineStart(s))
+		}
+		inline = nil
+	}
+	for _, node := range g.Flatten(nodes) {
+		if !blockTags[tagOf(node)] {
+			inline = append(inline, node)
+			continue
+		}
+		flush()
+		if s := me.block(node.(*g.Element)); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	flush()
+	return parts
+}
+
+func (me *renderer) block(e *g.Element) string {
+	tag := strings.ToLower(e.Tag)
+	switch {
+	case skippedTags[tag]:
+		return ""
+	case containerTags[tag]:
+		return me.blocks(e.Children)
+	}
+
+	switch tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(tag[1] - '0')
+		return strings.Repeat("#", level) + " " + strings.TrimSpace(me.inlines(e.Children))
+	case "p":
+		return escapeLineStart(strings.TrimSpace(me.inlines(e.Children)))
+	case "hr":
+		return "---"
+	case "pre":
+		return me.codeBlock(e)
+	case "blockquote":
+		inner := me.blocks(e.Children)
+		lines := strings.Split(inner, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return strings.Join(lines, "\n")
+	case "ul", "ol", "menu":
+		return me.list(e)
+	case "table":
+		if s, ok := me.table(e); ok {
+			return s
+		}
+	}
+	return me.html(e)
+}
+
+func (me *renderer) codeBlock(e *g.Element) string {
+	lang := ""
+	content := e.Children
+	if children := g.Flatten(e.Children); len(children) == 1 && tagOf(children[0]) == "code" {
+		code := children[0].(*g.Element)
+		content = code.Children
+		class := me.attrs(code)["class"]
+		for _, c := range strings.Fields(class) {
+			if l, ok := strings.CutPrefix(c, "language-"); ok {
+				lang = l
+			}
+		}
+	}
+
+	text := strings.TrimSuffix(me.rawText(content), "\n")
+	fence := strings.Repeat("`", max(3, longestRun(text, '`')+1))
+	return fence + lang + "\n" + text + "\n" + fence
+}
+
+func (me *renderer) list(e *g.Element) string {
+	ordered := strings.EqualFold(e.Tag, "ol")
+	n := 1
+	if start, err := strconv.Atoi(me.attrs(e)["start"]); ordered && err == nil {
+		n = start
+	}
+
+	var items []string
+	loose := false
+	for _, child := range g.Flatten(e.Children) {
+		li, ok := child.(*g.Element)
+		if !ok || !strings.EqualFold(li.Tag, "li") {
+			if s := strings.TrimSpace(me.blocks([]g.Node{child})); s != "" {
+				items = append(items, s)
+			}
+			continue
+		}
+
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(n) + ". "
+			n++
+		}
+		// The blocks of a tight item aren't separated by blank lines, which
+		// would make the list loose, unless a paragraph can't be followed
+		// by the next block otherwise.
+		parts := me.blockParts(li.Children)
+		tight := !slices.ContainsFunc(g.Flatten(li.Children), isParagraph)
+		content := ""
+		for i, part := range parts {
+			if i > 0 {
+				if tight && interruptsParagraph(part) {
+					content += "\n"
+				} else {
+					content += "\n\n"
+					loose = true
+				}
+			}
+			content += part
+		}
+		loose = loose || !tight
+		indent := strings.Repeat(" ", len(marker))
+		lines := strings.Split(content, "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = indent + lines[i]
+			}
+		}
+		items = append(items, marker+strings.Join(lines, "\n"))
+	}
+
+	if loose {
+		return strings.Join(items, "\n\n")
+	}
+	return strings.Join(items, "\n")
+}
+
+// interruptsParagraph reports whether block can start on the line after a
+// paragraph. Ordered lists only can when they start at 1.
+func interruptsParagraph(block string) bool {
+	m := orderedMarker.FindStringSubmatch(block)
+	return m == nil || m[1] == "1"
+}
+
+func isParagraph(node g.Node) bool {
+	return tagOf(node) == "p"
+}
+
+// table renders e as a GFM table, and reports false when e can't be one.
+func (me *renderer) table(e *g.Element) (string, bool) {
+	var rows []*g.Element
+	for _, child := range g.Flatten(e.Children) {
+		switch tagOf(child) {
+		case "thead", "tbody", "tfoot":
+			for _, row := range g.Flatten(child.(*g.Element).Children) {
+				if tagOf(row) != "tr" {
+					return "", false
+				}
+				rows = append(rows, row.(*g.Element))
+			}
+		case "tr":
+			rows = append(rows, child.(*g.Element))
+		case "":
+			if t, ok := child.(g.Text); !ok || strings.TrimSpace(string(t)) != "" {
+				return "", false
+			}
+		default: // caption, colgroup
+			return "", false
+		}
+	}
+	if len(rows) == 0 {
+		return "", false
+	}
+
+	var lines []string
+	var aligns []string
+	for i, row := range rows {
+		var cells []string
+		for _, cell := range g.Flatten(row.Children) {
+			tag := tagOf(cell)
+			if tag != "th" && tag != "td" {
+				return "", false
+			}
+			if (i == 0) != (tag == "th") {
+				return "", false // the first row, and only it, must be a header
+			}
+			c := cell.(*g.Element)
+			for _, n := range g.Flatten(c.Children) {
+				if blockTags[tagOf(n)] {
+					return "", false
+				}
+			}
+			// a line break would split the row
+			hasBreak := false
+			g.Walk(c, func(n g.Node) bool {
+				hasBreak = hasBreak || tagOf(n) == "br"
+				return !hasBreak
+			})
+			if hasBreak {
+				return "", false
+			}
+			if i == 0 {
+				aligns = append(aligns, me.align(c))
+			}
+			text := strings.TrimSpace(me.inlines(c.Children))
+			cells = append(cells, strings.ReplaceAll(text, "|", `\|`))
+		}
+		for len(cells) < len(aligns) {
+			cells = append(cells, "")
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "| "+strings.Join(aligns, " | ")+" |")
+		}
+	}
+	return strings.Join(lines, "\n"), true
+}
+
+// align returns the delimiter of the column of the header cell e.
+func (me *renderer) align(e *g.Element) string {
+	attrs := me.attrs(e)
+	align := attrs["align"]
+	if m := textAlign.FindStringSubmatch(attrs["style"]); m != nil {
+		align = m[1]
+	}
+	switch strings.ToLower(align) {
+	case "left":
+		return ":--"
+	case "center":
+		return ":-:"
+	case "right":
+		return "--:"
+	default:
+		return "---"
+	}
+}
+
+var textAlign = regexp.MustCompile(`text-align:\s*(\w+)`)
+
+// inlines renders nodes as inline Markdown.
+func (me *renderer) inlines(nodes []g.Node) string {
+	var sb strings.Builder
+	var text strings.Builder // adjacent Text nodes, merged
+	flushText := func() {
+		sb.WriteString(escapeText(g.CollapseSpace(text.String())))
+		text.Reset()
+	}
+	for _, node := range g.Flatten(nodes) {
+		if t, ok := node.(g.Text); ok {
+			text.WriteString(string(t))
+			continue
+		}
+		flushText()
+		sb.WriteString(me.inline(node))
+	}
+	flushText()
+	return sb.String()
+}
+
+func (me *renderer) inline(node g.Node) string {
+	e, ok := node.(*g.Element)
+	if !ok {
+		return me.html(node)
+	}
+
+	tag := strings.ToLower(e.Tag)
+	switch tag {
+	case "em", "i":
+		return wrap("*", me.inlines(e.Children))
+	case "strong", "b":
+		return wrap("**", me.inlines(e.Children))
+	case "del", "s":
+		return wrap("~~", me.inlines(e.Children))
+	case "code", "kbd", "samp":
+		if len(e.Attrs) == 0 || tag == "code" {
+			return codeSpan(g.CollapseSpace(me.rawText(e.Children)))
+		}
+	case "br":
+		return hardBreak
+	case "a":
+		attrs := me.attrs(e)
+		href, ok := attrs["href"]
+		if !ok {
+			break
+		}
+		text := me.inlines(e.Children)
+		if text == href && strings.Contains(href, ":") && !strings.ContainsAny(href, " <>") {
+			return "<" + href + ">"
+		}
+		return "[" + text + "](" + destination(href) + title(attrs) + ")"
+	case "img":
+		attrs := me.attrs(e)
+		return "![" + escapeText(attrs["alt"]) + "](" + destination(attrs["src"]) + title(attrs) + ")"
+	case "input":
+		if t := me.attrs(e)["type"]; strings.EqualFold(t, "checkbox") {
+			if _, checked := me.attrs(e)["checked"]; checked {
+				return "[x]"
+			}
+			return "[ ]"
+		}
+	}
+	if skippedTags[tag] {
+		return ""
+	}
+	return me.html(e)
+}
+
+// wrap surrounds s with delim, keeping the surrounding spaces outside, as
+// Markdown requires.
+func wrap(delim, s string) string {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return s
+	}
+	start := s[:strings.Index(s, trimmed)]
+	end := s[len(start)+len(trimmed):]
+	return start + delim + trimmed + delim + end
+}
+
+func codeSpan(s string) string {
+	fence := strings.Repeat("`", longestRun(s, '`')+1)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
+func destination(url string) string {
+	if url == "" || strings.ContainsAny(url, " ()<>") {
+		return "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(url) + ">"
+	}
+	return url
+}
+
+func title(attrs map[string]string) string {
+	t, ok := attrs["title"]
+	if !ok {
+		return ""
+	}
+	return ` "` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(t) + `"`
+}
+
+// html renders node as HTML, for nodes without a Markdown equivalent.
+func (me *renderer) html(node g.Node) string {
+	s, err := node.Render()
+	if err != nil && me.err == nil {
+		me.err = err
+	}
+	return s
+}
+
+// attrs returns the attributes of e as rendered by g, with URL checks and
+// computed values applied.
+func (me *renderer) attrs(e *g.Element) map[string]string {
+	s := me.html(&g.Element{Tag: e.Tag, IsVoid: true, Attrs: e.Attrs})
+	z := html.NewTokenizer(strings.NewReader(s))
+	z.Next()
+	result := map[string]string{}
+	for _, attr := range z.Token().Attr {
+		result[attr.Key] = attr.Val
+	}
+	return result
+}
+
+// rawText returns the text of nodes, without collapsing whitespace.
+func (me *renderer) rawText(nodes []g.Node) string {
+	var sb strings.Builder
+	for _, node := range g.Flatten(nodes) {
+		switch n := node.(type) {
+		case g.Text:
+			sb.WriteString(string(n))
+		case g.CodeText:
+			sb.WriteString(string(n))
+		case *g.Element:
+			sb.WriteString(me.rawText(n.Children))
+		default:
+			sb.WriteString(html.UnescapeString(me.html(n)))
+		}
+	}
+	return sb.String()
+}
+
+// hardBreak is the Markdown of a Br.
+const hardBreak = "\\\n"
+
+var (
+	textEscaper = strings.NewReplacer(
+		`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+		`<`, `\<`, `>`, `\>`, `~`, `\~`,
+	)
+	entity         = regexp.MustCompile(`&(#[0-9]+|#[xX][0-9a-fA-F]+|[A-Za-z][A-Za-z0-9]*);`)
+	orderedMarker  = regexp.MustCompile(`^([0-9]+)([.)])( |$)`)
+	blockLineStart = regexp.MustCompile(`^[#+=-]`)
+)
+
+// escapeText escapes the characters of s that Markdown would interpret.
+func escapeText(s string) string {
+	s = textEscaper.Replace(s)
+	return entity.ReplaceAllString(s, `\&$1;`)
+}
+
+// escapeLineStart escapes the start of the lines of a paragraph, the first
+// one and those after a hard break, that Markdown would read as a heading,
+// list item or blockquote.
+func escapeLineStart(s string) string {
+	lines := strings.Split(s, hardBreak)
+	for i, line := range lines {
+		if i > 0 {
+			line = strings.TrimLeft(line, " ")
+		}
+		if m := orderedMarker.FindStringSubmatch(line); m != nil {
+			line = m[1] + `\` + line[len(m[1]):]
+		} else if blockLineStart.MatchString(line) {
+			line = `\` + line
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, hardBreak)
+}
+
+func longestRun(s string, ch byte) int {
+	longest, run := 0, 0
+	for i := range len(s) {
+		if s[i] == ch {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
@@ -0,0 +1,142 @@
+package markdown
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    g.Node
+		expected string
+	}{
+		{
+			name:     "Headings and inline formatting",
+			input:    g.Empty(g.H2(g.Text("Title")), g.P(g.Text("Some "), g.Em(g.Text("em ")), g.Strong(g.Text("strong")), g.Text(" and "), g.Del(g.Text("old")), g.Text(".")), g.Hr()),
+			expected: "## Title\n\nSome *em* **strong** and ~~old~~.\n\n---\n",
+		},
+		{
+			name:     "Escaping",
+			input:    g.Empty(g.P(g.Text("1. a *b* [c] <d> x_y AT&T &copy;")), g.P(g.Text("# not a heading"))),
+			expected: "1\\. a \\*b\\* \\[c\\] \\<d\\> x\\_y AT&T \\&copy;\n\n\\# not a heading\n",
+		},
+		{
+			name:     "Links and images",
+			input:    g.P(g.A(g.KV{"href": "/docs", "title": `"Docs"`}, g.Text("docs")), g.Text(" "), g.A(g.KV{"href": "https://example.com"}, g.Text("https://example.com")), g.Text(" "), g.Img(g.KV{"src": "/a b.png", "alt": "logo"})),
+			expected: "[docs](/docs \"\\\"Docs\\\"\") <https://example.com> ![logo](</a b.png>)\n",
+		},
+		{
+			name:     "Unsafe URL",
+			input:    g.P(g.A(g.KV{"href": "javascript:alert(1)"}, g.Text("x"))),
+			expected: "[x](about:invalid#g-unsafe-url)\n",
+		},
+		{
+			name:     "Code",
+			input:    g.Empty(g.P(g.Code(g.Text("a`b"))), g.Pre(g.Code(g.KV{"class": "language-go"}, g.Text("if x {\n\treturn\n}\n")))),
+			expected: "``a`b``\n\n```go\nif x {\n\treturn\n}\n```\n",
+		},
+		{
+			name:     "Lists",
+			input:    g.Empty(g.Ul(g.Li(g.Text("a")), g.Li(g.Text("b"), g.Ol(g.Li(g.Text("c")), g.Li(g.Text("d")))))),
+			expected: "- a\n- b\n  1. c\n  2. d\n",
+		},
+		{
+			name:     "Ordered list that can't interrupt a paragraph",
+			input:    g.Ul(g.Li(g.Text("a"), g.Ol(g.KV{"start": "3"}, g.Li(g.Text("c"))))),
+			expected: "- a\n\n  3. c\n",
+		},
+		{
+			name:     "Loose list",
+			input:    g.Ul(g.Li(g.P(g.Text("a"))), g.Li(g.P(g.Text("b")))),
+			expected: "- a\n\n- b\n",
+		},
+		{
+			name:     "Blockquote and line break",
+			input:    g.Blockquote(g.P(g.Text("a"), g.Br(), g.Text("b")), g.P(g.Text("c"))),
+			expected: "> a\\\n> b\n>\n> c\n",
+		},
+		{
+			name:     "Line start after a line break is escaped",
+			input:    g.P(g.Text("a"), g.Br(), g.Text("# b"), g.Br(), g.Text(" 2. c"), g.Em(g.Text("d"), g.Br(), g.Text("> e"))),
+			expected: "a\\\n\\# b\\\n2\\. c*d\\\n\\> e*\n",
+		},
+		{
+			name: "Table",
+			input: g.Table(
+				g.Thead(g.Tr(g.Th(g.KV{"style": "text-align: right"}, g.Text("a")), g.Th(g.Text("b")))),
+				g.Tbody(g.Tr(g.Td(g.Text("1|2")), g.Td(g.Code(g.Text("x|y"))))),
+			),
+			expected: "| a | b |\n| --: | --- |\n| 1\\|2 | `x\\|y` |\n",
+		},
+		{
+			name:     "Table without a header falls back to HTML",
+			input:    g.Table(g.Tr(g.Td(g.Text("1")))),
+			expected: "<table><tr><td>1</td></tr></table>\n",
+		},
+		{
+			name:     "Table with a line break falls back to HTML",
+			input:    g.Table(g.Tr(g.Th(g.Text("a"))), g.Tr(g.Td(g.B(g.Text("1"), g.Br(), g.Text("2"))))),
+			expected: "<table><tr><th>a</th></tr><tr><td><b>1<br>2</b></td></tr></table>\n",
+		},
+		{
+			name:     "Elements without Markdown equivalent fall back to HTML",
+			input:    g.Div(g.P(g.Text("H"), g.Sub(g.Text("2")), g.Text("O")), g.Dl(g.Dt(g.Text("t")), g.Dd(g.Text("d")))),
+			expected: "H<sub>2</sub>O\n\n<dl><dt>t</dt><dd>d</dd></dl>\n",
+		},
+		{
+			name:     "Head and scripts are skipped",
+			input:    g.Html(g.Head(g.Title(g.Text("T"))), g.Body(g.H1(g.Text("Hi")), g.Script(g.Text("x()")))),
+			expected: "# Hi\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := Render(&sb, tt.input); err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("Render() = %q, want %q", sb.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestRender_RoundTrip(t *testing.T) {
+	tests := []string{
+		"# Title\n\nSome *em*, **strong**, `code` and ~~old~~ text.\n",
+		"- [x] done\n- [ ] todo\n",
+		"1. a\n2. b\n   - c\n",
+		"> quoted \\*text\\*\n>\n> - item\n",
+		"```go\nfunc main() {}\n```\n",
+		"| a | b |\n| :-- | :-: |\n| 1 | 2 |\n",
+		"[link](/x \"title\") ![img](/i.png)\n",
+		"a\\\n\\# b\n",
+	}
+
+	for _, input := range tests {
+		var sb strings.Builder
+		if err := Render(&sb, Parse(input)); err != nil {
+			t.Fatalf("Render() error: %v", err)
+		}
+		if sb.String() != input {
+			t.Errorf("Render(Parse(%q)) = %q", input, sb.String())
+		}
+	}
+}
+
+type failing struct{}
+
+func (failing) Render() (string, error) { return "", errors.New("boom") }
+
+func TestRender_Error(t *testing.T) {
+	var sb strings.Builder
+	if err := Render(&sb, g.P(failing{})); err == nil || err.Error() != "boom" {
+		t.Errorf("Render() error = %v, want boom", err)
+	}
+}
@@ -0,0 +1,53 @@
+package g
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithNonce(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     Node
+		expected string
+	}{
+		{
+			name:     "Scripts, styles and links",
+			node:     Div(Script(Text("x()")), Style(Text("p{}")), Link(KV{"rel": "stylesheet", "href": "/a.css"}), Img(KV{"src": "/a.png"})),
+			expected: `<div><script nonce="n">x()</script><style nonce="n">p{}</style><link href="/a.css" nonce="n" rel="stylesheet"><img src="/a.png"></div>`,
+		},
+		{
+			name:     "Existing nonce is replaced",
+			node:     Script(KV{"nonce": "old", "src": "/a.js"}),
+			expected: `<script nonce="n" src="/a.js"></script>`,
+		},
+		{
+			name:     "Hoisted items",
+			node:     Html(Head(), Body(HeadItem(Script(KV{"src": "/a.js"})))),
+			expected: `<html><head><script nonce="n" src="/a.js"></script></head><body></body></html>`,
+		},
+		{
+			name:     "Items without head",
+			node:     Div(HeadItem(Style(Text("p{}")))),
+			expected: `<div><style nonce="n">p{}</style></div>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, tt.node, WithNonce("n")); err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Render() = %q, want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+
+	script := Script(KV{"src": "/a.js"})
+	Render(&bytes.Buffer{}, script, WithNonce("n"))
+	if _, ok := script.Attrs["nonce"]; ok {
+		t.Errorf("Render() added the nonce to the element")
+	}
+}
@@ -1,6 +1,10 @@
 package g
 
-import "io"
+import (
+	"fmt"
+	"io"
+	"strings"
+)
 
 // Render writes the HTML representation of a Node to the provided io.Writer.
 //
@@ -8,12 +12,26 @@ import "io"
 // the output to an io.Writer, making it suitable for writing directly to
 // files, HTTP responses, or other output streams.
 //
+// Options change how the elements of the tree are rendered. Nodes other than
+// Text and *Element are rendered with their own Render method, so options
+// don't reach the elements they contain.
+//
 // Example:
 //
 //	err := Render(os.Stdout, Div(Text("Hello")))
 //	// Outputs: <div>Hello</div>
-func Render(writer io.Writer, node Node) error {
-	s, err := node.Render()
+func Render(writer io.Writer, node Node, opts ...RenderOption) error {
+	r := newRenderer(opts...)
+	if r.root == nil {
+		r.root = node
+	}
+	if r.strict {
+		if err := strictErr(node); err != nil {
+			return err
+		}
+	}
+
+	s, err := r.renderNode(node)
 	if err != nil {
 		return err
 	}
@@ -21,6 +39,73 @@ func Render(writer io.Writer, node Node) error {
 	return err
 }
 
+// RenderOption configures Render.
+type RenderOption func(*renderer)
+
+// renderer holds the configuration of a single render, shared by every
+// element of the tree.
+type renderer struct {
+	urlPolicy URLPolicy
+	strict    bool
+	nonce     string // set on script, style and link elements
+
+	root Node         // the document, where UniqueIDs are resolved
+	ids  *idGenerator // created on the first UniqueID
+
+	hoisted bool // whether the HeadItems were rendered in Head
+}
+
+func newRenderer(opts ...RenderOption) *renderer {
+	r := &renderer{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// renderNode renders node, passing the renderer down when it is an element.
+func (me *renderer) renderNode(node Node) (string, error) {
+	e, ok := node.(*Element)
+	if !ok {
+		return node.Render()
+	}
+	builder := &strings.Builder{}
+	if err := e.render(builder, me); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+// id returns the value generated for id in the document.
+func (me *renderer) id(id *ID) string {
+	if me.ids == nil {
+		me.ids = newIDGenerator(me.root)
+	}
+	return me.ids.name(id)
+}
+
+// attrString returns the rendered (not yet escaped) value of a non-boolean
+// attribute.
+func (me *renderer) attrString(tag, key string, value any) (string, error) {
+	var s string
+	switch v := value.(type) {
+	case TrustedURL:
+		return string(v), nil
+	case string:
+		s = v
+	case *ID:
+		s = me.id(v)
+	case AttrValuer:
+		var err error
+		if s, err = v.AttrValue(); err != nil {
+			return "", fmt.Errorf("attribute '%s': %w", key, err)
+		}
+	default:
+		return "", fmt.Errorf("attribute value must be string or bool, got %T for key '%s'", v, key)
+	}
+	return me.checkURL(tag, key, s)
+}
+
 // Node represents any renderable HTML element or text content.
 //
 // The Node interface is the core abstraction that allows both HTML elements
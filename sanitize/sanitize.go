@@ -0,0 +1,184 @@
+// Package sanitize turns untrusted HTML into a safe g tree, keeping only the
+// elements and attributes allowed by a policy.
+//
+// The input is parsed the way browsers parse it (golang.org/x/net/html), so
+// markup can't sneak past the policy by being malformed. Event handler
+// attributes (on*) are always dropped, and URL attributes only keep relative
+// URLs and the allowed schemes.
+package sanitize
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/assaidy/g"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Policy is an allowlist of elements and attributes.
+//
+// Elements that aren't allowed are removed, but their content is kept,
+// except for the elements in DropContent, which are removed with their
+// content.
+//
+// Example:
+//
+//	policy := &sanitize.Policy{
+//		Elements:   map[string][]string{"p": nil, "a": {"href"}},
+//		URLSchemes: []string{"https"},
+//	}
+//	node, err := policy.Sanitize(comment.Body)
+type Policy struct {
+	// Elements maps the allowed tags to the attributes allowed on them.
+	Elements map[string][]string
+	// GlobalAttrs are allowed on every allowed element.
+	GlobalAttrs []string
+	// URLSchemes are the schemes allowed in URL attributes (href, src,
+	// etc.). Relative URLs are always allowed.
+	URLSchemes []string
+	// DropContent lists the tags removed together with their content.
+	DropContent []string
+	// LinkRel, when set, is the rel attribute of every link with an href.
+	LinkRel string
+}
+
+// Strict returns a policy that keeps only text.
+func Strict() *Policy {
+	return &Policy{DropContent: defaultDropContent()}
+}
+
+// UGC returns a policy suited to user generated content: text formatting,
+// headings, lists, quotes, code, tables, links and images.
+func UGC() *Policy {
+	cells := []string{"colspan", "rowspan"}
+	return &Policy{
+		Elements: map[string][]string{
+			"a": {"href", "title"}, "abbr": {"title"}, "b": nil, "blockquote": {"cite"},
+			"br": nil, "caption": nil, "cite": nil, "code": nil, "dd": nil, "del": nil,
+			"details": nil, "dfn": nil, "div": nil, "dl": nil, "dt": nil, "em": nil,
+			"figcaption": nil, "figure": nil, "h1": nil, "h2": nil, "h3": nil, "h4": nil,
+			"h5": nil, "h6": nil, "hr": nil, "i": nil, "img": {"src", "alt", "title", "width", "height"},
+			"ins": nil, "kbd": nil, "li": nil, "mark": nil, "ol": {"start", "reversed"},
+			"p": nil, "pre": nil, "q": {"cite"}, "s": nil, "samp": nil, "small": nil,
+			"span": nil, "strong": nil, "sub": nil, "summary": nil, "sup": nil,
+			"table": nil, "tbody": nil, "td": cells, "tfoot": nil, "th": append(cells, "scope"),
+			"thead": nil, "time": {"datetime"}, "tr": nil, "u": nil, "ul": nil, "var": nil,
+		},
+		GlobalAttrs: []string{"dir", "lang"},
+		URLSchemes:  []string{"http", "https", "mailto"},
+		DropContent: defaultDropContent(),
+		LinkRel:     "nofollow ugc noopener",
+	}
+}
+
+func defaultDropContent() []string {
+	return []string{
+		"script", "style", "iframe", "object", "embed", "template", "noscript",
+		"textarea", "title", "select", "svg", "math", "frameset", "noembed", "noframes",
+	}
+}
+
+// urlAttrs are the attributes holding a URL.
+var urlAttrs = map[string]bool{
+	"action": true, "background": true, "cite": true, "formaction": true,
+	"href": true, "longdesc": true, "poster": true, "src": true, "xlink:href": true,
+}
+
+// Sanitize parses untrusted HTML and returns the parts allowed by the
+// policy, wrapped in a tagless element.
+func (me *Policy) Sanitize(untrusted string) (*g.Element, error) {
+	context := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(untrusted), context)
+	if err != nil {
+		return nil, err
+	}
+
+	result := g.Empty()
+	for _, n := range nodes {
+		result.Children = append(result.Children, me.convert(n)...)
+	}
+	return result, nil
+}
+
+// convert returns the nodes n turns into: nothing, n itself, or the children
+// of n when its tag isn't allowed.
+func (me *Policy) convert(n *html.Node) []g.Node {
+	switch n.Type {
+	case html.TextNode:
+		if inPre(n) {
+			return []g.Node{g.CodeText(n.Data)}
+		}
+		return []g.Node{g.Text(n.Data)}
+	case html.ElementNode:
+	default: // comments, doctypes
+		return nil
+	}
+
+	tag := strings.ToLower(n.Data)
+	if n.Namespace != "" || slices.Contains(me.DropContent, tag) {
+		return nil
+	}
+
+	var children []g.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		children = append(children, me.convert(c)...)
+	}
+
+	allowedAttrs, ok := me.Elements[tag]
+	if !ok {
+		return children
+	}
+
+	e := &g.Element{Tag: tag, IsVoid: g.IsVoidTag(tag)}
+	for _, attr := range n.Attr {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || strings.HasPrefix(key, "on") {
+			continue
+		}
+		if !slices.Contains(allowedAttrs, key) && !slices.Contains(me.GlobalAttrs, key) {
+			continue
+		}
+		if urlAttrs[key] && !me.allowedURL(attr.Val) {
+			continue
+		}
+		if e.Attrs == nil {
+			e.Attrs = g.KV{}
+		}
+		e.Attrs[key] = attr.Val
+	}
+	if _, ok := e.Attrs["href"]; ok && tag == "a" && me.LinkRel != "" {
+		e.Attrs["rel"] = me.LinkRel
+	}
+	if !e.IsVoid {
+		e.Children = children
+	}
+	if preTags[tag] && len(e.Children) > 0 {
+		// browsers drop the newline right after the start tag
+		if text, ok := e.Children[0].(g.CodeText); ok && strings.HasPrefix(string(text), "\n") {
+			e.Children[0] = "\n" + text
+		}
+	}
+
+	return []g.Node{e}
+}
+
+// allowedURL reports whether s is a relative URL or uses an allowed scheme.
+func (me *Policy) allowedURL(s string) bool {
+	scheme := g.URLScheme(s)
+	return scheme == "" || slices.Contains(me.URLSchemes, scheme)
+}
+
+// preTags are the elements whose text keeps its whitespace.
+var preTags = map[string]bool{"pre": true, "textarea": true, "listing": true}
+
+// inPre reports whether the text node n is in an element keeping its
+// whitespace.
+func inPre(n *html.Node) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.Namespace == "" && preTags[p.Data] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package sanitize
+
+import (
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+func TestPolicy_Sanitize(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   *Policy
+		input    string
+		expected string
+	}{
+		{
+			name:     "Allowed markup is kept",
+			policy:   UGC(),
+			input:    `<p>Hello <strong>world</strong><br><em>!</em></p>`,
+			expected: `<p>Hello <strong>world</strong><br><em>!</em></p>`,
+		},
+		{
+			name:     "Whitespace of code blocks is kept",
+			policy:   UGC(),
+			input:    "<pre><code>a\n  b &lt;c&gt;</code></pre><p>d\n  e</p>",
+			expected: "<pre><code>a\n  b &lt;c&gt;</code></pre><p>d e</p>",
+		},
+		{
+			name:     "Leading newline of code blocks is kept",
+			policy:   UGC(),
+			input:    "<pre>\n\na</pre>",
+			expected: "<pre>\n\na</pre>",
+		},
+		{
+			name:     "Script is removed with its content",
+			policy:   UGC(),
+			input:    `<p>hi</p><script>alert(1)</script>`,
+			expected: `<p>hi</p>`,
+		},
+		{
+			name:     "Disallowed element is unwrapped",
+			policy:   UGC(),
+			input:    `<form action="/x"><b>bold</b></form>`,
+			expected: `<b>bold</b>`,
+		},
+		{
+			name:     "Event handlers are dropped",
+			policy:   UGC(),
+			input:    `<img src="a.png" onerror="alert(1)" alt="a">`,
+			expected: `<img alt="a" src="a.png">`,
+		},
+		{
+			name:     "Disallowed attributes are dropped",
+			policy:   UGC(),
+			input:    `<p style="color:red" class="x" lang="en">x</p>`,
+			expected: `<p lang="en">x</p>`,
+		},
+		{
+			name:     "javascript: URL is dropped",
+			policy:   UGC(),
+			input:    `<a href="javascript:alert(1)">x</a>`,
+			expected: `<a>x</a>`,
+		},
+		{
+			name:     "Obfuscated javascript: URL is dropped",
+			policy:   UGC(),
+			input:    `<a href=" java&#x09;script&colon;alert(1)">x</a>`,
+			expected: `<a>x</a>`,
+		},
+		{
+			name:     "Relative URL net/url can't parse is kept",
+			policy:   UGC(),
+			input:    `<a href="/50%off.pdf">x</a>`,
+			expected: `<a href="/50%off.pdf" rel="nofollow ugc noopener">x</a>`,
+		},
+		{
+			name:     "data: URL is dropped",
+			policy:   UGC(),
+			input:    `<img src="data:image/svg+xml;base64,PHN2Zz4=">`,
+			expected: `<img>`,
+		},
+		{
+			name:     "Allowed and relative URLs are kept",
+			policy:   UGC(),
+			input:    `<a href="https://example.com/?a=1&amp;b=2">x</a><a href="/about">y</a>`,
+			expected: `<a href="https://example.com/?a=1&amp;b=2" rel="nofollow ugc noopener">x</a><a href="/about" rel="nofollow ugc noopener">y</a>`,
+		},
+		{
+			name:     "Malformed markup is parsed like a browser",
+			policy:   UGC(),
+			input:    `<p><b>unclosed<p>next`,
+			expected: `<p><b>unclosed</b></p><p><b>next</b></p>`,
+		},
+		{
+			name:     "SVG is removed",
+			policy:   UGC(),
+			input:    `<svg><script>alert(1)</script></svg>ok`,
+			expected: `ok`,
+		},
+		{
+			name:     "Comments are removed",
+			policy:   UGC(),
+			input:    `a<!-- <script>alert(1)</script> -->b`,
+			expected: `ab`,
+		},
+		{
+			name:     "Strict keeps only text",
+			policy:   Strict(),
+			input:    `<h1>Title</h1><p>a &lt; b</p><style>p{}</style>`,
+			expected: `Titlea &lt; b`,
+		},
+		{
+			name: "Custom policy",
+			policy: &Policy{
+				Elements:   map[string][]string{"a": {"href"}, "p": nil},
+				URLSchemes: []string{"https"},
+			},
+			input:    `<p><a href="http://x.com">x</a><a href="https://x.com">y</a><i>z</i></p>`,
+			expected: `<p><a>x</a><a href="https://x.com">y</a>z</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := tt.policy.Sanitize(tt.input)
+			if err != nil {
+				t.Fatalf("Sanitize() error: %v", err)
+			}
+			result, err := node.Render()
+			if err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPolicy_Sanitize_InPage(t *testing.T) {
+	body, err := UGC().Sanitize(`<b onclick="x()">hi</b>`)
+	if err != nil {
+		t.Fatalf("Sanitize() error: %v", err)
+	}
+	result, err := g.Div(g.KV{"class": "comment"}, body).Render()
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	if result != `<div class="comment"><b>hi</b></div>` {
+		t.Errorf("Render() = %q, want %q", result, `<div class="comment"><b>hi</b></div>`)
+	}
+}
@@ -0,0 +1,311 @@
+package selector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (me *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("selector %q: at offset %d: %s", me.src, me.pos, fmt.Sprintf(format, args...))
+}
+
+func (me *parser) eof() bool {
+	return me.pos >= len(me.src)
+}
+
+func (me *parser) peek() byte {
+	if me.eof() {
+		return 0
+	}
+	return me.src[me.pos]
+}
+
+func (me *parser) skipSpace() bool {
+	start := me.pos
+	for !me.eof() && isSpace(me.peek()) {
+		me.pos++
+	}
+	return me.pos > start
+}
+
+// list parses a selector list, up to end (0 for the end of the input).
+func (me *parser) list(end byte) ([]*Selector, error) {
+	var sels []*Selector
+	for {
+		me.skipSpace()
+		start := me.pos
+		sel, err := me.complex(end)
+		if err != nil {
+			return nil, err
+		}
+		sel.src = strings.TrimSpace(me.src[start:me.pos])
+		sels = append(sels, sel)
+
+		me.skipSpace()
+		switch {
+		case me.peek() == ',':
+			me.pos++
+		case me.peek() == end && (end != 0 || me.eof()):
+			return sels, nil
+		default:
+			return nil, me.errorf("unexpected %q", me.peek())
+		}
+	}
+}
+
+// complex parses compound selectors separated by combinators.
+func (me *parser) complex(end byte) (*Selector, error) {
+	sel := &Selector{}
+	combinator := byte(0)
+	for {
+		c, err := me.compound()
+		if err != nil {
+			return nil, err
+		}
+		c.combinator = combinator
+		sel.parts = append(sel.parts, c)
+
+		space := me.skipSpace()
+		switch ch := me.peek(); {
+		case ch == '>' || ch == '+' || ch == '~':
+			combinator = ch
+			me.pos++
+			me.skipSpace()
+		case ch == ',' || ch == end || me.eof():
+			return sel, nil
+		case space:
+			combinator = ' '
+		default:
+			return nil, me.errorf("unexpected %q", ch)
+		}
+	}
+}
+
+// compound parses a sequence of simple selectors, such as a.link[href].
+func (me *parser) compound() (compound, error) {
+	var c compound
+	start := me.pos
+
+	if me.peek() == '*' {
+		me.pos++
+		c.tag = "*"
+	} else if isNameStart(me.peek()) {
+		c.tag = strings.ToLower(me.ident())
+	}
+
+	for !me.eof() {
+		switch me.peek() {
+		case '#':
+			me.pos++
+			id := me.ident()
+			if id == "" {
+				return c, me.errorf("expected an id")
+			}
+			c.ids = append(c.ids, id)
+		case '.':
+			me.pos++
+			class := me.ident()
+			if class == "" {
+				return c, me.errorf("expected a class name")
+			}
+			c.classes = append(c.classes, class)
+		case '[':
+			me.pos++
+			a, err := me.attr()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, a)
+		case ':':
+			me.pos++
+			p, err := me.pseudo()
+			if err != nil {
+				return c, err
+			}
+			c.pseudos = append(c.pseudos, p)
+		default:
+			if me.pos == start {
+				return c, me.errorf("expected a selector")
+			}
+			return c, nil
+		}
+	}
+	if me.pos == start {
+		return c, me.errorf("expected a selector")
+	}
+	return c, nil
+}
+
+// attr parses an attribute selector, after the '['.
+func (me *parser) attr() (attrSelector, error) {
+	me.skipSpace()
+	a := attrSelector{key: strings.ToLower(me.ident())}
+	if a.key == "" {
+		return a, me.errorf("expected an attribute name")
+	}
+	me.skipSpace()
+
+	if me.peek() == ']' {
+		me.pos++
+		return a, nil
+	}
+	if strings.ContainsRune("~|^$*", rune(me.peek())) {
+		a.op = string(me.peek())
+		me.pos++
+	}
+	if me.peek() != '=' {
+		return a, me.errorf("expected '=' in attribute selector")
+	}
+	a.op += "="
+	me.pos++
+	me.skipSpace()
+
+	if q := me.peek(); q == '"' || q == '\'' {
+		me.pos++
+		end := strings.IndexByte(me.src[me.pos:], q)
+		if end < 0 {
+			return a, me.errorf("unterminated string")
+		}
+		a.value = me.src[me.pos : me.pos+end]
+		me.pos += end + 1
+	} else {
+		a.value = me.ident()
+		if a.value == "" {
+			return a, me.errorf("expected an attribute value")
+		}
+	}
+
+	me.skipSpace()
+	if me.peek() == 'i' || me.peek() == 'I' {
+		a.ignoreCase = true
+		me.pos++
+		me.skipSpace()
+	}
+	if me.peek() != ']' {
+		return a, me.errorf("expected ']'")
+	}
+	me.pos++
+	return a, nil
+}
+
+// pseudo parses a pseudo-class, after the ':'.
+func (me *parser) pseudo() (pseudo, error) {
+	if me.peek() == ':' {
+		return pseudo{}, me.errorf("pseudo-elements aren't supported")
+	}
+	p := pseudo{name: strings.ToLower(me.ident())}
+	switch p.name {
+	case "first-child", "last-child", "only-child", "first-of-type", "last-of-type",
+		"empty", "root", "checked", "disabled", "enabled", "required", "optional":
+		return p, nil
+	case "not", "is", "where", "nth-child", "nth-last-child", "nth-of-type", "nth-last-of-type":
+	case "":
+		return p, me.errorf("expected a pseudo-class")
+	default:
+		return p, me.errorf("unsupported pseudo-class :%s", p.name)
+	}
+
+	if me.peek() != '(' {
+		return p, me.errorf("expected '(' after :%s", p.name)
+	}
+	me.pos++
+	me.skipSpace()
+
+	if p.name == "not" || p.name == "is" || p.name == "where" {
+		sels, err := me.list(')')
+		if err != nil {
+			return p, err
+		}
+		me.pos++
+		p.args = sels
+		return p, nil
+	}
+
+	end := strings.IndexByte(me.src[me.pos:], ')')
+	if end < 0 {
+		return p, me.errorf("expected ')'")
+	}
+	a, b, ok := parseNth(strings.TrimSpace(me.src[me.pos : me.pos+end]))
+	if !ok {
+		return p, me.errorf("invalid argument of :%s", p.name)
+	}
+	p.a, p.b = a, b
+	me.pos += end + 1
+	return p, nil
+}
+
+// ident parses a CSS identifier, with backslash escapes.
+func (me *parser) ident() string {
+	var sb strings.Builder
+	for !me.eof() {
+		ch := me.peek()
+		switch {
+		case ch == '\\' && me.pos+1 < len(me.src):
+			me.pos++
+			r, size := utf8.DecodeRuneInString(me.src[me.pos:])
+			sb.WriteRune(r)
+			me.pos += size
+		case isNameChar(ch):
+			sb.WriteByte(ch)
+			me.pos++
+		default:
+			return sb.String()
+		}
+	}
+	return sb.String()
+}
+
+// parseNth parses the an+b argument of :nth-child and friends.
+func parseNth(s string) (a, b int, ok bool) {
+	s = strings.ToLower(strings.ReplaceAll(s, " ", ""))
+	switch s {
+	case "odd":
+		return 2, 1, true
+	case "even":
+		return 2, 0, true
+	}
+
+	i := strings.IndexByte(s, 'n')
+	if i < 0 {
+		b, err := strconv.Atoi(s)
+		return 0, b, err == nil
+	}
+
+	switch coef := s[:i]; coef {
+	case "", "+":
+		a = 1
+	case "-":
+		a = -1
+	default:
+		var err error
+		if a, err = strconv.Atoi(coef); err != nil {
+			return 0, 0, false
+		}
+	}
+	if rest := s[i+1:]; rest != "" {
+		var err error
+		if b, err = strconv.Atoi(rest); err != nil || (rest[0] != '+' && rest[0] != '-') {
+			return 0, 0, false
+		}
+	}
+	return a, b, true
+}
+
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' || ch == '\f'
+}
+
+func isNameStart(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch == '_' || ch == '-' || ch == '\\' || ch >= 0x80
+}
+
+func isNameChar(ch byte) bool {
+	return isNameStart(ch) && ch != '\\' || ch >= '0' && ch <= '9'
+}
@@ -0,0 +1,377 @@
+// Package selector matches CSS selectors against g trees.
+//
+// It supports type, universal, id, class and attribute selectors, the
+// descendant, child (>), next-sibling (+) and subsequent-sibling (~)
+// combinators, selector lists, and the structural and form pseudo-classes
+// that don't depend on user interaction (:first-child, :nth-child(2n+1),
+// :not(...), :checked, etc.). Pseudo-elements and pseudo-classes such as
+// :hover are reported as parse errors.
+//
+// Example:
+//
+//	inputs, err := selector.Select(page, "form input[required]")
+package selector
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/assaidy/g"
+)
+
+// Selector is a parsed complex selector, such as "ul > li.active".
+type Selector struct {
+	src   string
+	parts []compound // from left to right
+}
+
+// compound is a sequence of simple selectors, with the combinator that
+// links it to the previous compound.
+type compound struct {
+	combinator byte // 0 for the first compound, or ' ', '>', '+', '~'
+	tag        string
+	ids        []string
+	classes    []string
+	attrs      []attrSelector
+	pseudos    []pseudo
+}
+
+type attrSelector struct {
+	key        string
+	op         string // "", "=", "~=", "|=", "^=", "$=" or "*="
+	value      string
+	ignoreCase bool
+}
+
+type pseudo struct {
+	name string
+	args []*Selector // for :not, :is and :where
+	a, b int         // for :nth-child and friends
+}
+
+// Parse parses a selector list, such as "h1, h2 > a".
+func Parse(s string) ([]*Selector, error) {
+	p := &parser{src: s}
+	return p.list(0)
+}
+
+// MustParse is like Parse but panics on errors. It is meant for selectors
+// known at compile time.
+func MustParse(s string) []*Selector {
+	sels, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return sels
+}
+
+// String returns the source of the selector.
+func (me *Selector) String() string {
+	return me.src
+}
+
+// Specificity returns the specificity of the selector: the number of id
+// selectors, of class, attribute and pseudo-class selectors, and of type
+// selectors.
+//
+// https://www.w3.org/TR/selectors-4/#specificity-rules
+func (me *Selector) Specificity() [3]int {
+	var s [3]int
+	for _, c := range me.parts {
+		s = add(s, c.specificity())
+	}
+	return s
+}
+
+func (me compound) specificity() [3]int {
+	s := [3]int{len(me.ids), len(me.classes) + len(me.attrs), 0}
+	if me.tag != "" && me.tag != "*" {
+		s[2]++
+	}
+	for _, p := range me.pseudos {
+		switch p.name {
+		case "where":
+		case "not", "is":
+			// the most specific argument
+			var most [3]int
+			for _, arg := range p.args {
+				if spec := arg.Specificity(); CompareSpecificity(spec, most) > 0 {
+					most = spec
+				}
+			}
+			s = add(s, most)
+		default:
+			s[1]++
+		}
+	}
+	return s
+}
+
+func add(a, b [3]int) [3]int {
+	return [3]int{a[0] + b[0], a[1] + b[1], a[2] + b[2]}
+}
+
+// CompareSpecificity returns -1, 0 or +1 as a is less, as or more specific
+// than b.
+func CompareSpecificity(a, b [3]int) int {
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Select returns the elements of root matching any of the selectors in
+// the selector list s, in document order.
+func Select(root g.Node, s string) ([]*g.Element, error) {
+	sels, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return SelectParsed(root, sels...), nil
+}
+
+// SelectParsed returns the elements of root matching any of sels, in
+// document order.
+func SelectParsed(root g.Node, sels ...*Selector) []*g.Element {
+	var result []*g.Element
+	Walk(root, func(e *g.Element, pos *Position) {
+		if slices.ContainsFunc(sels, func(sel *Selector) bool { return sel.Matches(pos) }) {
+			result = append(result, e)
+		}
+	})
+	return result
+}
+
+// Position is the place of an element in a tree: its parent and siblings.
+// Tagless elements are transparent: their children are siblings of their
+// own siblings.
+type Position struct {
+	Element  *g.Element
+	Parent   *Position // nil for the roots
+	Siblings []*g.Element
+	Index    int // of Element in Siblings
+}
+
+// Walk calls fn for every element of root, in document order, with its
+// position in the tree.
+func Walk(root g.Node, fn func(e *g.Element, pos *Position)) {
+	walk(elements([]g.Node{root}), nil, fn)
+}
+
+func walk(siblings []*g.Element, parent *Position, fn func(*g.Element, *Position)) {
+	for i, e := range siblings {
+		pos := &Position{Element: e, Parent: parent, Siblings: siblings, Index: i}
+		fn(e, pos)
+		walk(elements(e.Children), pos, fn)
+	}
+}
+
+// elements returns the element children, with tagless elements replaced by
+// their own children.
+func elements(nodes []g.Node) []*g.Element {
+	var result []*g.Element
+	for _, node := range nodes {
+		e, ok := node.(*g.Element)
+		if !ok || e == nil {
+			continue
+		}
+		if e.Tag == "" {
+			result = append(result, elements(e.Children)...)
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// Matches reports whether the element at pos matches the selector.
+func (me *Selector) Matches(pos *Position) bool {
+	return me.matchFrom(len(me.parts)-1, pos)
+}
+
+// matchFrom reports whether pos matches parts[:i+1], parts[i] being matched
+// by pos itself.
+func (me *Selector) matchFrom(i int, pos *Position) bool {
+	c := me.parts[i]
+	if !c.matches(pos) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	switch c.combinator {
+	case '>':
+		return pos.Parent != nil && me.matchFrom(i-1, pos.Parent)
+	case ' ':
+		for p := pos.Parent; p != nil; p = p.Parent {
+			if me.matchFrom(i-1, p) {
+				return true
+			}
+		}
+	case '+':
+		return pos.Index > 0 && me.matchFrom(i-1, sibling(pos, pos.Index-1))
+	case '~':
+		for j := pos.Index - 1; j >= 0; j-- {
+			if me.matchFrom(i-1, sibling(pos, j)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sibling(pos *Position, i int) *Position {
+	return &Position{Element: pos.Siblings[i], Parent: pos.Parent, Siblings: pos.Siblings, Index: i}
+}
+
+func (me compound) matches(pos *Position) bool {
+	e := pos.Element
+	if me.tag != "" && me.tag != "*" && !strings.EqualFold(e.Tag, me.tag) {
+		return false
+	}
+	for _, id := range me.ids {
+		if v, ok := Attr(e, "id"); !ok || v != id {
+			return false
+		}
+	}
+	for _, class := range me.classes {
+		v, _ := Attr(e, "class")
+		if !slices.Contains(strings.Fields(v), class) {
+			return false
+		}
+	}
+	for _, a := range me.attrs {
+		if !a.matches(e) {
+			return false
+		}
+	}
+	for _, p := range me.pseudos {
+		if !p.matches(pos) {
+			return false
+		}
+	}
+	return true
+}
+
+func (me attrSelector) matches(e *g.Element) bool {
+	v, ok := Attr(e, me.key)
+	if !ok {
+		return false
+	}
+	want := me.value
+	if me.ignoreCase {
+		v, want = strings.ToLower(v), strings.ToLower(want)
+	}
+	switch me.op {
+	case "":
+		return true
+	case "=":
+		return v == want
+	case "~=":
+		return slices.Contains(strings.Fields(v), want)
+	case "|=":
+		return v == want || strings.HasPrefix(v, want+"-")
+	case "^=":
+		return want != "" && strings.HasPrefix(v, want)
+	case "$=":
+		return want != "" && strings.HasSuffix(v, want)
+	case "*=":
+		return want != "" && strings.Contains(v, want)
+	}
+	return false
+}
+
+func (me pseudo) matches(pos *Position) bool {
+	e := pos.Element
+	switch me.name {
+	case "first-child":
+		return pos.Index == 0
+	case "last-child":
+		return pos.Index == len(pos.Siblings)-1
+	case "only-child":
+		return len(pos.Siblings) == 1
+	case "first-of-type":
+		return nth(pos, 0, 1, false, true)
+	case "last-of-type":
+		return nth(pos, 0, 1, true, true)
+	case "nth-child":
+		return nth(pos, me.a, me.b, false, false)
+	case "nth-last-child":
+		return nth(pos, me.a, me.b, true, false)
+	case "nth-of-type":
+		return nth(pos, me.a, me.b, false, true)
+	case "nth-last-of-type":
+		return nth(pos, me.a, me.b, true, true)
+	case "root":
+		return pos.Parent == nil
+	case "empty":
+		// nil, Text("") and empty tagless elements render nothing
+		return len(g.Flatten(e.Children)) == 0
+	case "checked":
+		return hasBool(e, "checked") || hasBool(e, "selected")
+	case "disabled":
+		return hasBool(e, "disabled")
+	case "enabled":
+		return !hasBool(e, "disabled")
+	case "required":
+		return hasBool(e, "required")
+	case "optional":
+		return !hasBool(e, "required")
+	case "not":
+		return !slices.ContainsFunc(me.args, func(sel *Selector) bool { return sel.Matches(pos) })
+	case "is", "where":
+		return slices.ContainsFunc(me.args, func(sel *Selector) bool { return sel.Matches(pos) })
+	}
+	return false
+}
+
+// nth reports whether pos is at a position a*n+b, counted from 1, among
+// its siblings (of the same type, if ofType is true).
+func nth(pos *Position, a, b int, fromEnd, ofType bool) bool {
+	index := 0
+	for i, s := range pos.Siblings {
+		if ofType && !strings.EqualFold(s.Tag, pos.Element.Tag) {
+			continue
+		}
+		if !fromEnd && i > pos.Index || fromEnd && i < pos.Index {
+			continue
+		}
+		index++
+	}
+	if a == 0 {
+		return index == b
+	}
+	n := index - b
+	return n%a == 0 && n/a >= 0
+}
+
+func hasBool(e *g.Element, key string) bool {
+	_, ok := Attr(e, key)
+	return ok
+}
+
+// Attr returns the value of the attribute key of e as it is rendered, and
+// whether it is rendered at all. Boolean attributes have an empty value, and
+// ids generated by g.UniqueID, which are only known when rendering, have
+// none either.
+func Attr(e *g.Element, key string) (string, bool) {
+	switch v := e.Attrs[key].(type) {
+	case string:
+		return v, true
+	case bool:
+		return "", v
+	case g.AttrValuer:
+		s, err := v.AttrValue()
+		return s, err == nil
+	case *g.ID:
+		return "", true
+	default:
+		return "", false
+	}
+}
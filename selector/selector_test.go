@@ -0,0 +1,130 @@
+package selector
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+func TestSelect(t *testing.T) {
+	page := g.Div(g.KV{"id": "app"},
+		g.H1(g.KV{"class": "title main"}, g.Text("Title")),
+		g.Ul(
+			g.Li(g.KV{"key": "a"}, g.A(g.KV{"href": "https://example.com"}, g.Text("A"))),
+			g.Empty(g.Li(g.KV{"key": "b", "class": "active"}, g.A(g.KV{"href": "/b", "lang": "en-US"}, g.Text("B")))),
+			g.Li(g.KV{"key": "c"}),
+		),
+		g.Form(
+			g.Input(g.KV{"name": "email", "required": true}),
+			g.Input(g.KV{"name": "password", "required": false, "disabled": true}),
+		),
+		g.P(g.Text("x")),
+	)
+
+	tests := []struct {
+		name     string
+		selector string
+		expected []string // the key, name or tag of the matches
+	}{
+		{name: "Type", selector: "li", expected: []string{"a", "b", "c"}},
+		{name: "Universal", selector: "ul > *", expected: []string{"a", "b", "c"}},
+		{name: "Id and descendant", selector: "#app a", expected: []string{"a", "a"}},
+		{name: "Classes", selector: ".title.main", expected: []string{"h1"}},
+		{name: "Missing class", selector: ".title.other", expected: nil},
+		{name: "Attribute present", selector: "[required]", expected: []string{"email"}},
+		{name: "Attribute value", selector: `input[name="password"]`, expected: []string{"password"}},
+		{name: "Unquoted value", selector: "input[name=password]", expected: []string{"password"}},
+		{name: "Prefix", selector: "a[href^=https]", expected: []string{"a"}},
+		{name: "Suffix and case", selector: "a[href$=B i]", expected: []string{"a"}},
+		{name: "Dash match", selector: "[lang|=en]", expected: []string{"a"}},
+		{name: "Child", selector: "div > li", expected: nil},
+		{name: "Next sibling through tagless element", selector: "li.active + li", expected: []string{"c"}},
+		{name: "Subsequent sibling", selector: "h1 ~ p", expected: []string{"p"}},
+		{name: "First and last child", selector: "li:first-child, li:last-child", expected: []string{"a", "c"}},
+		{name: "Nth child", selector: "li:nth-child(2n+1)", expected: []string{"a", "c"}},
+		{name: "Nth child even", selector: "li:nth-child(even)", expected: []string{"b"}},
+		{name: "Not", selector: "li:not(.active)", expected: []string{"a", "c"}},
+		{name: "Empty", selector: "li:empty", expected: []string{"c"}},
+		{name: "Disabled", selector: "input:disabled", expected: []string{"password"}},
+		{name: "Root", selector: ":root", expected: []string{"div"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := Select(page, tt.selector)
+			if err != nil {
+				t.Fatalf("Select() error: %v", err)
+			}
+			var result []string
+			for _, e := range found {
+				switch {
+				case e.Attrs["key"] != nil:
+					result = append(result, e.Attrs["key"].(string))
+				case e.Attrs["name"] != nil:
+					result = append(result, e.Attrs["name"].(string))
+				default:
+					result = append(result, e.Tag)
+				}
+			}
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("Select(%q) = %q, want %q", tt.selector, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSelect_Empty(t *testing.T) {
+	page := g.Div(
+		g.P(g.KV{"key": "nothing"}),
+		g.P(g.KV{"key": "empty text"}, g.Text("")),
+		g.P(g.KV{"key": "empty tagless"}, g.Empty(g.Text(""))),
+		g.P(g.KV{"key": "space"}, g.Text(" ")),
+		g.P(g.KV{"key": "element"}, g.Br()),
+	)
+	found, err := Select(page, "p:empty")
+	if err != nil {
+		t.Fatalf("Select() error: %v", err)
+	}
+	var result []string
+	for _, e := range found {
+		result = append(result, e.Attrs["key"].(string))
+	}
+	if want := []string{"nothing", "empty text", "empty tagless"}; !slices.Equal(result, want) {
+		t.Errorf("Select(p:empty) = %q, want %q", result, want)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, s := range []string{"", "a >", "a,", "[x", "[x=]", `[x="y]`, "a:hover", "a::before", ".", "#", "li:nth-child(x)", "a b)"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) should fail", s)
+		}
+	}
+}
+
+func TestSelector_Specificity(t *testing.T) {
+	tests := []struct {
+		selector string
+		expected [3]int
+	}{
+		{"*", [3]int{0, 0, 0}},
+		{"li", [3]int{0, 0, 1}},
+		{"ul li.active", [3]int{0, 1, 2}},
+		{"#app > a[href]:first-child", [3]int{1, 2, 1}},
+		{"a:not(#x, .y)", [3]int{1, 0, 1}},
+		{"a:where(#x)", [3]int{0, 0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			sels, err := Parse(tt.selector)
+			if err != nil {
+				t.Fatalf("Parse() error: %v", err)
+			}
+			if result := sels[0].Specificity(); result != tt.expected {
+				t.Errorf("Specificity() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+// Package seo renders the metadata of a page for search engines and link
+// previews: title, description, canonical URL, robots directives, hreflang
+// alternates, OpenGraph, Twitter cards and favicons.
+//
+// Example:
+//
+//	page := seo.Page{
+//		Title:         post.Title,
+//		TitleTemplate: "%s | Acme",
+//		Description:   post.Summary,
+//		Canonical:     "https://acme.com/blog/" + post.Slug,
+//		OpenGraph: seo.OpenGraph{
+//			Type:   "article",
+//			Images: []seo.Image{{URL: post.Cover, Alt: post.CoverAlt, Width: 1200, Height: 630}},
+//		},
+//		Twitter: seo.Twitter{Card: "summary_large_image", Site: "@acme"},
+//	}
+//	g.Html(g.Head(g.Meta(g.KV{"charset": "utf-8"}), page.Head()), g.Body(...))
+package seo
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/assaidy/g"
+)
+
+// Page is the metadata of a page.
+type Page struct {
+	Title string
+	// TitleTemplate formats Title in the title element, e.g., "%s | Acme".
+	// OpenGraph and Twitter cards get the Title alone.
+	TitleTemplate string
+	// DefaultTitle is used, without the template, when Title is empty.
+	DefaultTitle string
+	Description  string
+	// Canonical is the absolute URL of the page. It is also the default
+	// og:url.
+	Canonical  string
+	Robots     Robots
+	Alternates []Alternate
+	OpenGraph  OpenGraph
+	Twitter    Twitter
+	Icons      []Icon
+}
+
+// Robots are the directives of the robots meta tag.
+//
+// https://developers.google.com/search/docs/crawling-indexing/robots-meta-tag
+type Robots struct {
+	NoIndex   bool
+	NoFollow  bool
+	NoArchive bool
+	NoSnippet bool
+	// Extra directives, such as "max-image-preview:large".
+	Extra []string
+}
+
+func (me Robots) content() string {
+	var directives []string
+	for _, d := range []struct {
+		set  bool
+		name string
+	}{{me.NoIndex, "noindex"}, {me.NoFollow, "nofollow"}, {me.NoArchive, "noarchive"}, {me.NoSnippet, "nosnippet"}} {
+		if d.set {
+			directives = append(directives, d.name)
+		}
+	}
+	return strings.Join(append(directives, me.Extra...), ", ")
+}
+
+// Alternate is a version of the page in another language, for hreflang.
+type Alternate struct {
+	Lang string // a language tag, such as "fr" or "en-GB", or "x-default"
+	URL  string // absolute
+}
+
+// OpenGraph is the OpenGraph metadata of the page, for link previews. It
+// is rendered when any field is set; Title, Description and URL default to
+// those of the Page, and Type to "website".
+//
+// https://ogp.me
+type OpenGraph struct {
+	Type        string
+	Title       string
+	Description string
+	URL         string
+	SiteName    string
+	Locale      string // such as "en_US"
+	Images      []Image
+}
+
+func (me OpenGraph) isZero() bool {
+	return me.Type == "" && me.Title == "" && me.Description == "" && me.URL == "" &&
+		me.SiteName == "" && me.Locale == "" && len(me.Images) == 0
+}
+
+// Image is an OpenGraph image.
+type Image struct {
+	URL    string // absolute
+	Alt    string
+	Type   string // MIME type, such as "image/png"
+	Width  int
+	Height int
+}
+
+// Twitter is the Twitter (X) card of the page. It is rendered when Card is
+// set; the missing fields fall back to the OpenGraph tags.
+//
+// https://developer.x.com/en/docs/x-for-websites/cards/overview/markup
+type Twitter struct {
+	Card        string // "summary", "summary_large_image", "app" or "player"
+	Site        string // @username of the website
+	Creator     string // @username of the author
+	Title       string
+	Description string
+	Image       string // absolute URL
+	ImageAlt    string
+}
+
+// Icon is a favicon, such as {Rel: "icon", Href: "/favicon.svg", Type:
+// "image/svg+xml"} or {Rel: "apple-touch-icon", Href: "/apple.png", Sizes:
+// "180x180"}. Rel defaults to "icon".
+type Icon struct {
+	Rel   string
+	Href  string
+	Type  string
+	Sizes string
+	Color string // for Rel "mask-icon"
+}
+
+// title returns the content of the title element.
+func (me Page) title() string {
+	if me.Title == "" {
+		return me.DefaultTitle
+	}
+	if me.TitleTemplate == "" {
+		return me.Title
+	}
+	return strings.Replace(me.TitleTemplate, "%s", me.Title, 1)
+}
+
+// Head returns the metadata elements of the page, as a tagless element for
+// Head. Empty fields are left out. The elements come in this order: title,
+// description, robots, canonical, alternates, OpenGraph, Twitter, icons.
+//
+// The elements deduplicate like g.HeadItem, so a page can override the
+// metadata of its layout with g.HeadItem(page.Head()): the OpenGraph
+// images of the page replace the default image of the layout, all of them
+// kept in order.
+func (me Page) Head() *g.Element {
+	h := &head{}
+	if title := me.title(); title != "" {
+		h.add(g.Title(g.Text(title)))
+	}
+	h.meta("name", "description", me.Description)
+	h.meta("name", "robots", me.Robots.content())
+	h.link(g.KV{"rel": "canonical", "href": me.Canonical})
+	for _, a := range me.Alternates {
+		h.link(g.KV{"rel": "alternate", "hreflang": a.Lang, "href": a.URL})
+	}
+
+	if og := me.OpenGraph; !og.isZero() {
+		h.meta("property", "og:type", or(og.Type, "website"))
+		h.meta("property", "og:title", or(og.Title, me.Title, me.DefaultTitle))
+		h.meta("property", "og:description", or(og.Description, me.Description))
+		h.meta("property", "og:url", or(og.URL, me.Canonical))
+		h.meta("property", "og:site_name", og.SiteName)
+		h.meta("property", "og:locale", og.Locale)
+		for _, img := range og.Images {
+			h.meta("property", "og:image", img.URL)
+			h.meta("property", "og:image:alt", img.Alt)
+			h.meta("property", "og:image:type", img.Type)
+			if img.Width > 0 && img.Height > 0 {
+				h.meta("property", "og:image:width", strconv.Itoa(img.Width))
+				h.meta("property", "og:image:height", strconv.Itoa(img.Height))
+			}
+		}
+	}
+
+	if tw := me.Twitter; tw.Card != "" {
+		h.meta("name", "twitter:card", tw.Card)
+		h.meta("name", "twitter:site", tw.Site)
+		h.meta("name", "twitter:creator", tw.Creator)
+		h.meta("name", "twitter:title", tw.Title)
+		h.meta("name", "twitter:description", tw.Description)
+		h.meta("name", "twitter:image", tw.Image)
+		h.meta("name", "twitter:image:alt", tw.ImageAlt)
+	}
+
+	for _, icon := range me.Icons {
+		attrs := g.KV{"rel": or(icon.Rel, "icon"), "href": icon.Href}
+		for k, v := range map[string]string{"type": icon.Type, "sizes": icon.Sizes, "color": icon.Color} {
+			if v != "" {
+				attrs[k] = v
+			}
+		}
+		h.link(attrs)
+	}
+	return g.Empty(h.nodes...)
+}
+
+type head struct {
+	nodes []any
+}
+
+func (me *head) add(node g.Node) {
+	me.nodes = append(me.nodes, node)
+}
+
+func (me *head) meta(key, name, content string) {
+	if content != "" {
+		me.add(g.Meta(g.KV{key: name, "content": content}))
+	}
+}
+
+func (me *head) link(attrs g.KV) {
+	if attrs["href"] != "" {
+		me.add(g.Link(attrs))
+	}
+}
+
+// or returns the first non-empty value.
+func or(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Issue is a problem found by Validate, in the field Field of the Page,
+// such as "OpenGraph.Images[0].URL".
+type Issue struct {
+	Field    string
+	Severity g.Severity
+	Message  string
+}
+
+func (me Issue) String() string {
+	return fmt.Sprintf("%s at %s: %s", me.Severity, me.Field, me.Message)
+}
+
+// Recommended maximum lengths, in characters, beyond which search engines
+// truncate the title and description.
+const (
+	MaxTitleLength       = 60
+	MaxDescriptionLength = 160
+)
+
+var (
+	langTag    = regexp.MustCompile(`^([A-Za-z]{2,3}(-[A-Za-z]{4})?(-([A-Za-z]{2}|[0-9]{3}))?|x-default)$`)
+	twitterKey = map[string]bool{"summary": true, "summary_large_image": true, "app": true, "player": true}
+)
+
+// Validate checks the page: missing required fields, relative URLs and
+// invalid values are SeverityError issues, and too long or missing
+// recommended fields are SeverityWarning issues.
+//
+// Example:
+//
+//	for _, issue := range page.Validate() {
+//		log.Print(issue)
+//	}
+//	// warning at Description: 183 characters, longer than the recommended 160
+func (me Page) Validate() []Issue {
+	v := &validator{}
+
+	switch title := me.title(); {
+	case title == "":
+		v.add("Title", g.SeverityError, "the page has no title")
+	case utf8.RuneCountInString(title) > MaxTitleLength:
+		v.add("Title", g.SeverityWarning, "%d characters, longer than the recommended %d", utf8.RuneCountInString(title), MaxTitleLength)
+	}
+	if me.TitleTemplate != "" && strings.Count(me.TitleTemplate, "%s") != 1 {
+		v.add("TitleTemplate", g.SeverityError, "%q must contain %%s once", me.TitleTemplate)
+	}
+	switch n := utf8.RuneCountInString(me.Description); {
+	case n == 0:
+		v.add("Description", g.SeverityWarning, "the page has no description")
+	case n > MaxDescriptionLength:
+		v.add("Description", g.SeverityWarning, "%d characters, longer than the recommended %d", n, MaxDescriptionLength)
+	}
+	v.absolute("Canonical", me.Canonical, false)
+
+	langs := map[string]bool{}
+	for i, a := range me.Alternates {
+		field := fmt.Sprintf("Alternates[%d]", i)
+		switch lang := strings.ToLower(a.Lang); {
+		case !langTag.MatchString(a.Lang):
+			v.add(field+".Lang", g.SeverityError, "%q is not a language tag", a.Lang)
+		case langs[lang]:
+			v.add(field+".Lang", g.SeverityError, "%q has more than one alternate", a.Lang)
+		default:
+			langs[lang] = true
+		}
+		v.absolute(field+".URL", a.URL, true)
+	}
+
+	if og := me.OpenGraph; !og.isZero() {
+		if len(og.Images) == 0 {
+			v.add("OpenGraph.Images", g.SeverityError, "OpenGraph requires an image")
+		}
+		if og.URL == "" && me.Canonical == "" {
+			v.add("OpenGraph.URL", g.SeverityError, "OpenGraph requires a URL, or a Canonical URL")
+		}
+		v.absolute("OpenGraph.URL", og.URL, false)
+		for i, img := range og.Images {
+			field := fmt.Sprintf("OpenGraph.Images[%d]", i)
+			v.absolute(field+".URL", img.URL, true)
+			if img.Alt == "" {
+				v.add(field+".Alt", g.SeverityWarning, "the image has no alternative text")
+			}
+			if (img.Width > 0) != (img.Height > 0) {
+				v.add(field, g.SeverityError, "Width and Height must be set together")
+			}
+		}
+	}
+
+	if tw := me.Twitter; tw.Card != "" {
+		if !twitterKey[tw.Card] {
+			v.add("Twitter.Card", g.SeverityError, "%q is not a card type", tw.Card)
+		}
+		for _, f := range []struct{ field, name string }{{"Twitter.Site", tw.Site}, {"Twitter.Creator", tw.Creator}} {
+			if f.name != "" && !strings.HasPrefix(f.name, "@") {
+				v.add(f.field, g.SeverityWarning, "%q should be an @username", f.name)
+			}
+		}
+		v.absolute("Twitter.Image", tw.Image, false)
+	}
+
+	for i, icon := range me.Icons {
+		if icon.Href == "" {
+			v.add(fmt.Sprintf("Icons[%d].Href", i), g.SeverityError, "the icon has no href")
+		}
+	}
+	return v.issues
+}
+
+type validator struct {
+	issues []Issue
+}
+
+func (me *validator) add(field string, severity g.Severity, format string, args ...any) {
+	me.issues = append(me.issues, Issue{Field: field, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// absolute checks that s is an absolute http or https URL.
+func (me *validator) absolute(field, s string, required bool) {
+	if s == "" {
+		if required {
+			me.add(field, g.SeverityError, "the URL is missing")
+		}
+		return
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		me.add(field, g.SeverityError, "%q is not an absolute http(s) URL", s)
+	}
+}
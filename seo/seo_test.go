@@ -0,0 +1,208 @@
+package seo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/assaidy/g"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPage_Head(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     Page
+		expected string
+	}{
+		{
+			name:     "Title template and description",
+			page:     Page{Title: "Post", TitleTemplate: "%s | Acme", Description: "A post."},
+			expected: `<title>Post | Acme</title><meta content="A post." name="description">`,
+		},
+		{
+			name:     "Default title",
+			page:     Page{TitleTemplate: "%s | Acme", DefaultTitle: "Acme"},
+			expected: `<title>Acme</title>`,
+		},
+		{
+			name: "Robots, canonical and alternates",
+			page: Page{
+				Title:      "Home",
+				Robots:     Robots{NoIndex: true, NoFollow: true, Extra: []string{"max-image-preview:large"}},
+				Canonical:  "https://acme.com/",
+				Alternates: []Alternate{{"fr", "https://acme.com/fr/"}, {"x-default", "https://acme.com/"}},
+			},
+			expected: `<title>Home</title><meta content="noindex, nofollow, max-image-preview:large" name="robots">` +
+				`<link href="https://acme.com/" rel="canonical">` +
+				`<link href="https://acme.com/fr/" hreflang="fr" rel="alternate">` +
+				`<link href="https://acme.com/" hreflang="x-default" rel="alternate">`,
+		},
+		{
+			name: "OpenGraph defaults to the page",
+			page: Page{
+				Title:         "Post",
+				TitleTemplate: "%s | Acme",
+				Description:   "A post.",
+				Canonical:     "https://acme.com/post",
+				OpenGraph:     OpenGraph{SiteName: "Acme", Images: []Image{{URL: "https://acme.com/a.png", Alt: "A", Width: 1200, Height: 630}}},
+			},
+			expected: `<title>Post | Acme</title><meta content="A post." name="description"><link href="https://acme.com/post" rel="canonical">` +
+				`<meta content="website" property="og:type"><meta content="Post" property="og:title"><meta content="A post." property="og:description">` +
+				`<meta content="https://acme.com/post" property="og:url"><meta content="Acme" property="og:site_name">` +
+				`<meta content="https://acme.com/a.png" property="og:image"><meta content="A" property="og:image:alt">` +
+				`<meta content="1200" property="og:image:width"><meta content="630" property="og:image:height">`,
+		},
+		{
+			name: "Twitter card and icons",
+			page: Page{
+				Title:   "Home",
+				Twitter: Twitter{Card: "summary", Site: "@acme", ImageAlt: ""},
+				Icons:   []Icon{{Href: "/favicon.svg", Type: "image/svg+xml"}, {Rel: "apple-touch-icon", Href: "/apple.png", Sizes: "180x180"}},
+			},
+			expected: `<title>Home</title><meta content="summary" name="twitter:card"><meta content="@acme" name="twitter:site">` +
+				`<link href="/favicon.svg" rel="icon" type="image/svg+xml"><link href="/apple.png" rel="apple-touch-icon" sizes="180x180">`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.page.Head().Render()
+			if err != nil {
+				t.Fatalf("Render() error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Head() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPage_HeadItem(t *testing.T) {
+	page := Page{Title: "Post", Description: "A post."}
+	result, err := g.Html(
+		g.Head(g.Title(g.Text("Acme")), g.Meta(g.KV{"name": "description", "content": "default"})),
+		g.Body(g.HeadItem(page.Head())),
+	).Render()
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	want := `<html><head><title>Post</title><meta content="A post." name="description"></head><body></body></html>`
+	if result != want {
+		t.Errorf("Render() = %q, want %q", result, want)
+	}
+}
+
+func TestPage_HeadItemImages(t *testing.T) {
+	page := Page{
+		Title:     "Post",
+		Canonical: "https://acme.com/post",
+		OpenGraph: OpenGraph{Images: []Image{{URL: "https://acme.com/a.png", Width: 1200, Height: 630}, {URL: "https://acme.com/b.png", Width: 1200, Height: 630}}},
+	}
+	result, err := g.Html(
+		g.Head(
+			g.Title(g.Text("Acme")),
+			g.Meta(g.KV{"property": "og:title", "content": "Acme"}),
+			g.Meta(g.KV{"property": "og:image", "content": "https://acme.com/default.png"}),
+			g.Meta(g.KV{"property": "og:image:alt", "content": "Acme"}),
+		),
+		g.Body(g.HeadItem(page.Head())),
+	).Render()
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	want := `<html><head><title>Post</title><meta content="Post" property="og:title">` +
+		`<link href="https://acme.com/post" rel="canonical"><meta content="website" property="og:type"><meta content="https://acme.com/post" property="og:url">` +
+		`<meta content="https://acme.com/a.png" property="og:image"><meta content="1200" property="og:image:width"><meta content="630" property="og:image:height">` +
+		`<meta content="https://acme.com/b.png" property="og:image"><meta content="1200" property="og:image:width"><meta content="630" property="og:image:height">` +
+		`</head><body></body></html>`
+	if result != want {
+		t.Errorf("Render() = %q, want %q", result, want)
+	}
+}
+
+func TestPage_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     Page
+		expected []string
+	}{
+		{
+			name: "Valid",
+			page: Page{
+				Title:       "Post",
+				Description: "A post.",
+				Canonical:   "https://acme.com/post",
+				Alternates:  []Alternate{{"en-GB", "https://acme.com/en-gb/post"}},
+				OpenGraph:   OpenGraph{Images: []Image{{URL: "https://acme.com/a.png", Alt: "A"}}},
+				Twitter:     Twitter{Card: "summary_large_image", Site: "@acme"},
+			},
+		},
+		{
+			name: "Missing and too long",
+			page: Page{TitleTemplate: "%s | Acme", DefaultTitle: strings.Repeat("a", 61)},
+			expected: []string{
+				"warning at Title: 61 characters, longer than the recommended 60",
+				"warning at Description: the page has no description",
+			},
+		},
+		{
+			name: "Invalid values",
+			page: Page{
+				TitleTemplate: "Acme",
+				Description:   "A post.",
+				Canonical:     "/post",
+				Alternates:    []Alternate{{"french", "https://acme.com/fr"}, {"de", ""}, {"DE", "https://acme.com/de"}},
+				Twitter:       Twitter{Card: "large", Creator: "bob"},
+				Icons:         []Icon{{Rel: "icon"}},
+			},
+			expected: []string{
+				"error at Title: the page has no title",
+				`error at TitleTemplate: "Acme" must contain %s once`,
+				`error at Canonical: "/post" is not an absolute http(s) URL`,
+				`error at Alternates[0].Lang: "french" is not a language tag`,
+				"error at Alternates[1].URL: the URL is missing",
+				`error at Alternates[2].Lang: "DE" has more than one alternate`,
+				`error at Twitter.Card: "large" is not a card type`,
+				`warning at Twitter.Creator: "bob" should be an @username`,
+				"error at Icons[0].Href: the icon has no href",
+			},
+		},
+		{
+			name: "OpenGraph",
+			page: Page{
+				Title:       "Post",
+				Description: "A post.",
+				OpenGraph:   OpenGraph{Type: "article"},
+			},
+			expected: []string{
+				"error at OpenGraph.Images: OpenGraph requires an image",
+				"error at OpenGraph.URL: OpenGraph requires a URL, or a Canonical URL",
+			},
+		},
+		{
+			name: "OpenGraph images",
+			page: Page{
+				Title:       "Post",
+				Description: "A post.",
+				OpenGraph:   OpenGraph{URL: "https://acme.com/post", Images: []Image{{URL: "a.png", Width: 100}}},
+			},
+			expected: []string{
+				`error at OpenGraph.Images[0].URL: "a.png" is not an absolute http(s) URL`,
+				"warning at OpenGraph.Images[0].Alt: the image has no alternative text",
+				"error at OpenGraph.Images[0]: Width and Height must be set together",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result []string
+			for _, issue := range tt.page.Validate() {
+				result = append(result, issue.String())
+			}
+			if diff := cmp.Diff(tt.expected, result); diff != "" {
+				t.Errorf("Validate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
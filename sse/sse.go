@@ -0,0 +1,128 @@
+// Package sse streams rendered nodes to the browser as Server-Sent Events.
+//
+// https://html.spec.whatwg.org/multipage/server-sent-events.html
+package sse
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/assaidy/g"
+)
+
+// Event is a single message of an event stream.
+type Event struct {
+	// ID is stored by the browser and sent back in the Last-Event-ID header
+	// when it reconnects.
+	ID string
+	// Name is the event type the browser dispatches, "message" when empty.
+	Name string
+	// Node is rendered into the data of the event.
+	Node g.Node
+	// Retry, when set, changes how long the browser waits before
+	// reconnecting.
+	Retry time.Duration
+}
+
+// Writer writes events to an HTTP response, flushing after each one.
+type Writer struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// NewWriter sets the event stream headers on w and sends them. It returns an
+// error if w can't be flushed.
+//
+// Example:
+//
+//	stream, err := sse.NewWriter(w)
+//	if err != nil {
+//		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+//		return
+//	}
+//	for p := range progress {
+//		err := stream.Send(sse.Event{Name: "progress", Node: progressBar(p)})
+//		...
+//	}
+func NewWriter(w http.ResponseWriter) (*Writer, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("sse: response writer does not support flushing")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &Writer{w: w, flusher: flusher}, nil
+}
+
+// Send renders the event's node and writes the event. Every line of the
+// rendered HTML becomes a "data:" line.
+func (me *Writer) Send(event Event) error {
+	if strings.ContainsAny(event.ID, "\r\n\x00") {
+		return fmt.Errorf("sse: invalid event id %q", event.ID)
+	}
+	if strings.ContainsAny(event.Name, "\r\n") {
+		return fmt.Errorf("sse: invalid event name %q", event.Name)
+	}
+
+	var data string
+	if event.Node != nil {
+		s, err := event.Node.Render()
+		if err != nil {
+			return err
+		}
+		data = s
+	}
+
+	builder := &strings.Builder{}
+	if event.ID != "" {
+		fmt.Fprintf(builder, "id: %s\n", event.ID)
+	}
+	if event.Name != "" {
+		fmt.Fprintf(builder, "event: %s\n", event.Name)
+	}
+	if event.Retry > 0 {
+		fmt.Fprintf(builder, "retry: %d\n", event.Retry.Milliseconds())
+	}
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\r", "\n")
+	for line := range strings.SplitSeq(data, "\n") {
+		fmt.Fprintf(builder, "data: %s\n", line)
+	}
+	fmt.Fprint(builder, "\n")
+
+	return me.write(builder.String())
+}
+
+// Comment writes a comment line, which the browser ignores. It is useful to
+// keep idle connections open.
+func (me *Writer) Comment(text string) error {
+	builder := &strings.Builder{}
+	for line := range strings.SplitSeq(strings.ReplaceAll(text, "\r", ""), "\n") {
+		fmt.Fprintf(builder, ": %s\n", line)
+	}
+	fmt.Fprint(builder, "\n")
+	return me.write(builder.String())
+}
+
+func (me *Writer) write(s string) error {
+	if _, err := me.w.Write([]byte(s)); err != nil {
+		return err
+	}
+	me.flusher.Flush()
+	return nil
+}
+
+// LastEventID returns the id of the last event the browser received before
+// reconnecting, or "" on the first connection. Streams can use it to resume
+// from where the client left off.
+func LastEventID(r *http.Request) string {
+	return r.Header.Get("Last-Event-ID")
+}
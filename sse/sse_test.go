@@ -0,0 +1,109 @@
+package sse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/assaidy/g"
+)
+
+func TestWriter_Send(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    Event
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Data only",
+			event:    Event{Node: g.P(g.Text("hi"))},
+			expected: "data: <p>hi</p>\n\n",
+		},
+		{
+			name:     "Id, name and retry",
+			event:    Event{ID: "42", Name: "progress", Retry: 3 * time.Second, Node: g.Progress(g.KV{"value": "42", "max": "100"})},
+			expected: "id: 42\nevent: progress\nretry: 3000\ndata: <progress max=\"100\" value=\"42\"></progress>\n\n",
+		},
+		{
+			name:     "Multi-line output",
+			event:    Event{Node: multiLine("<ul>\n<li>a</li>\r\n<li>b</li>\n</ul>")},
+			expected: "data: <ul>\ndata: <li>a</li>\ndata: <li>b</li>\ndata: </ul>\n\n",
+		},
+		{
+			name:    "Invalid id",
+			event:   Event{ID: "1\n2", Node: g.Text("x")},
+			wantErr: true,
+		},
+		{
+			name:    "Render error",
+			event:   Event{Node: g.Div(g.KV{"bad": nil})},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			stream, err := NewWriter(w)
+			if err != nil {
+				t.Fatalf("NewWriter() error: %v", err)
+			}
+
+			err = stream.Send(tt.event)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Send() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && w.Body.String() != tt.expected {
+				t.Errorf("Send() wrote %q, want %q", w.Body.String(), tt.expected)
+			}
+			if !w.Flushed {
+				t.Error("Send() should flush the response")
+			}
+		})
+	}
+}
+
+func TestNewWriter_Headers(t *testing.T) {
+	w := httptest.NewRecorder()
+	if _, err := NewWriter(w); err != nil {
+		t.Fatalf("NewWriter() error: %v", err)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", cc)
+	}
+}
+
+func TestWriter_Comment(t *testing.T) {
+	w := httptest.NewRecorder()
+	stream, _ := NewWriter(w)
+	if err := stream.Comment("keep-alive"); err != nil {
+		t.Fatalf("Comment() error: %v", err)
+	}
+	if w.Body.String() != ": keep-alive\n\n" {
+		t.Errorf("Comment() wrote %q, want %q", w.Body.String(), ": keep-alive\n\n")
+	}
+}
+
+func TestLastEventID(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/events", nil)
+	if id := LastEventID(r); id != "" {
+		t.Errorf("LastEventID() = %q on first connection, want empty", id)
+	}
+	r.Header.Set("Last-Event-ID", "7")
+	if id := LastEventID(r); id != "7" {
+		t.Errorf("LastEventID() = %q, want %q", id, "7")
+	}
+}
+
+// multiLine is a node rendering raw multi-line output, as custom nodes can.
+type multiLine string
+
+func (me multiLine) Render() (string, error) {
+	return string(me), nil
+}
@@ -0,0 +1,442 @@
+package g
+
+import (
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RenderText writes the plain-text representation of a Node to the provided
+// io.Writer, e.g., for the text part of an email or for search indexing.
+//
+// Tags are dropped and whitespace is collapsed as browsers do. Block
+// elements start new lines, and paragraphs, headings, lists, tables,
+// blockquotes and preformatted text are separated by blank lines:
+//   - H1 and H2 are underlined with = and -,
+//   - Ul and Ol items are prefixed with "- " and their number,
+//   - links are shown as "text (url)", unless the text is the URL,
+//   - images are replaced by their alt text,
+//   - table columns are aligned, and a header row is underlined,
+//   - blockquote lines are prefixed with "> ",
+//   - Pre keeps its whitespace.
+//
+// Head, Script, Style and Template contents are skipped. Nodes other than
+// Text and *Element are rendered as HTML and stripped of their tags.
+//
+// Example:
+//
+//	err := RenderText(os.Stdout, Div(P(Text("See")), Ul(Li(A(KV{"href": "/a"}, Text("A"))))))
+//	// Outputs:
+//	// See
+//	//
+//	// - A (/a)
+func RenderText(writer io.Writer, node Node, opts ...RenderOption) error {
+	r := newRenderer(opts...)
+	if r.root == nil {
+		r.root = node
+	}
+	w := &textWriter{r: r, lineStart: true}
+	if err := w.node(node); err != nil {
+		return err
+	}
+	s := w.sb.String()
+	if s != "" {
+		s += "\n"
+	}
+	_, err := writer.Write([]byte(s))
+	return err
+}
+
+// textMargins are the number of line breaks around block elements: 2 leaves
+// a blank line.
+var textMargins = map[string]int{
+	"address": 1, "article": 1, "aside": 1, "blockquote": 2, "body": 1,
+	"caption": 1, "dd": 1, "details": 1, "dialog": 1, "div": 1, "dl": 2,
+	"dt": 1, "fieldset": 1, "figcaption": 1, "figure": 2, "footer": 1,
+	"form": 1, "h1": 2, "h2": 2, "h3": 2, "h4": 2, "h5": 2, "h6": 2,
+	"header": 1, "hgroup": 1, "hr": 2, "html": 1, "legend": 1, "li": 1,
+	"main": 1, "menu": 2, "nav": 1, "ol": 2, "option": 1, "p": 2, "pre": 2,
+	"search": 1, "section": 1, "summary": 1, "table": 2, "ul": 2,
+}
+
+// textSkipped are the elements whose content isn't text for readers.
+var textSkipped = map[string]bool{
+	"head": true, "script": true, "style": true, "template": true,
+	"iframe": true, "object": true, "canvas": true, "svg": true, "math": true,
+}
+
+// textWriter lays out text line by line.
+type textWriter struct {
+	r  *renderer
+	sb strings.Builder
+
+	levels    []textLevel // the prefixes of the lines, e.g., for lists
+	newlines  int         // line breaks to write before the next text
+	space     bool        // whether to write a space before the next text
+	lineStart bool        // whether the line has no text yet
+	pre       int         // the depth of Pre elements
+	lists     int         // the depth of lists
+}
+
+type textLevel struct {
+	indent string // the prefix of the lines
+	marker string // the prefix of the next line, e.g., "1. "
+}
+
+// block requests n line breaks before the next text.
+func (me *textWriter) block(n int) {
+	me.newlines = max(me.newlines, n)
+	me.space = false
+}
+
+// prefix returns the prefix of a new line, using up the markers.
+func (me *textWriter) prefix() string {
+	var sb strings.Builder
+	for i := range me.levels {
+		if me.levels[i].marker != "" {
+			sb.WriteString(me.levels[i].marker)
+			me.levels[i].marker = ""
+		} else {
+			sb.WriteString(me.levels[i].indent)
+		}
+	}
+	return sb.String()
+}
+
+// indent returns the prefix of the lines, without markers.
+func (me *textWriter) indent() string {
+	var sb strings.Builder
+	for _, level := range me.levels {
+		sb.WriteString(level.indent)
+	}
+	return sb.String()
+}
+
+// blankPrefix returns the prefix of a blank line, e.g., ">" in blockquotes.
+func (me *textWriter) blankPrefix() string {
+	return strings.TrimRight(me.indent(), " ")
+}
+
+// startText writes the pending line breaks, prefix or space before text.
+func (me *textWriter) startText() {
+	if me.newlines > 0 && me.sb.Len() > 0 {
+		for i := range me.newlines {
+			if i > 0 {
+				me.sb.WriteString(me.blankPrefix())
+			}
+			me.sb.WriteByte('\n')
+		}
+		me.lineStart = true
+	}
+	me.newlines = 0
+	if me.lineStart {
+		me.sb.WriteString(me.prefix())
+		me.lineStart = false
+	} else if me.space {
+		me.sb.WriteByte(' ')
+	}
+	me.space = false
+}
+
+// text writes s, collapsing its whitespace unless in a Pre.
+func (me *textWriter) text(s string) {
+	for _, r := range s {
+		switch {
+		case me.pre > 0 && r == '\n':
+			me.newlines++
+		case me.pre > 0:
+			me.startText()
+			me.sb.WriteRune(r)
+		case unicode.IsSpace(r):
+			me.space = true
+		default:
+			me.startText()
+			me.sb.WriteRune(r)
+		}
+	}
+}
+
+// lines writes s as is, each line with the prefix.
+func (me *textWriter) lines(s string) {
+	me.pre++
+	me.text(s)
+	me.pre--
+}
+
+func (me *textWriter) children(nodes []Node) error {
+	for _, child := range nodes {
+		if err := me.node(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (me *textWriter) node(node Node) error {
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case Text:
+		me.text(string(n))
+		return nil
+	case CodeText:
+		me.lines(string(n))
+		return nil
+	case *Element:
+		return me.element(n)
+	case *headItem:
+		return nil // belongs in Head, which is skipped
+	default:
+		s, err := n.Render()
+		if err != nil {
+			return err
+		}
+		me.text(stripTags(s))
+		return nil
+	}
+}
+
+func (me *textWriter) element(e *Element) error {
+	tag := strings.ToLower(e.Tag)
+	if textSkipped[tag] {
+		return nil
+	}
+	margin := textMargins[tag]
+	if me.lists > 0 && (tag == "ul" || tag == "ol" || tag == "menu") {
+		margin = 1 // nested lists stick to their item
+	}
+	if margin > 0 {
+		me.block(margin)
+		defer me.block(margin)
+	}
+
+	switch tag {
+	case "br":
+		me.newlines++
+		return nil
+	case "hr":
+		me.lines("----")
+		return nil
+	case "img":
+		alt, err := me.attr(e, "alt")
+		me.text(alt)
+		return err
+	case "pre":
+		me.pre++
+		defer func() { me.pre-- }()
+	case "h1", "h2":
+		start := me.sb.Len()
+		if err := me.children(e.Children); err != nil {
+			return err
+		}
+		heading := me.sb.String()[start:]
+		line := heading[strings.LastIndexByte(heading, '\n')+1:]
+		if width := utf8.RuneCountInString(line) - utf8.RuneCountInString(me.indent()); width > 0 {
+			underline := "="
+			if tag == "h2" {
+				underline = "-"
+			}
+			me.block(1)
+			me.lines(strings.Repeat(underline, width))
+		}
+		return nil
+	case "a":
+		return me.link(e)
+	case "ul", "menu":
+		return me.list(e, func(int) string { return "- " })
+	case "ol":
+		n := 1
+		start, err := me.attr(e, "start")
+		if err != nil {
+			return err
+		}
+		if i, err := strconv.Atoi(start); err == nil {
+			n = i
+		}
+		return me.list(e, func(i int) string { return strconv.Itoa(n+i) + ". " })
+	case "blockquote":
+		me.levels = append(me.levels, textLevel{indent: "> "})
+		defer func() { me.levels = me.levels[:len(me.levels)-1] }()
+	case "dd":
+		me.levels = append(me.levels, textLevel{indent: "  "})
+		defer func() { me.levels = me.levels[:len(me.levels)-1] }()
+	case "table":
+		return me.table(e)
+	}
+	if e.IsVoid {
+		return nil
+	}
+	return me.children(e.Children)
+}
+
+func (me *textWriter) link(e *Element) error {
+	start := me.sb.Len()
+	if err := me.children(e.Children); err != nil {
+		return err
+	}
+	href, err := me.attr(e, "href")
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSpace(me.sb.String()[start:])
+	if href == "" || text == href || text == strings.TrimPrefix(href, "mailto:") {
+		return nil
+	}
+	me.space = me.space || me.sb.Len() > start
+	me.text("(" + href + ")")
+	return nil
+}
+
+// list writes the items of e, prefixed with marker(i) for the i-th item.
+func (me *textWriter) list(e *Element, marker func(int) string) error {
+	me.lists++
+	defer func() { me.lists-- }()
+	i := 0
+	for _, child := range Flatten(e.Children) {
+		li, ok := child.(*Element)
+		if !ok || !strings.EqualFold(li.Tag, "li") {
+			if err := me.node(child); err != nil {
+				return err
+			}
+			continue
+		}
+		m := marker(i)
+		i++
+		me.block(1)
+		me.levels = append(me.levels, textLevel{indent: strings.Repeat(" ", len(m)), marker: m})
+		err := me.children(li.Children)
+		me.levels = me.levels[:len(me.levels)-1]
+		if err != nil {
+			return err
+		}
+		me.block(1)
+	}
+	return nil
+}
+
+// table writes the rows of e with aligned columns. A first row made of Th
+// cells is underlined.
+func (me *textWriter) table(e *Element) error {
+	var rows [][]string
+	header := false
+	addRow := func(tr *Element) error {
+		var row []string
+		allTh := true
+		for _, cell := range Flatten(tr.Children) {
+			c, ok := cell.(*Element)
+			if !ok {
+				continue
+			}
+			tag := strings.ToLower(c.Tag)
+			if tag != "td" && tag != "th" {
+				continue
+			}
+			allTh = allTh && tag == "th"
+			cw := &textWriter{r: me.r, lineStart: true}
+			if err := cw.children(c.Children); err != nil {
+				return err
+			}
+			row = append(row, strings.Join(strings.Fields(cw.sb.String()), " "))
+		}
+		if len(rows) == 0 {
+			header = allTh && len(row) > 0
+		}
+		rows = append(rows, row)
+		return nil
+	}
+
+	for _, child := range Flatten(e.Children) {
+		c, ok := child.(*Element)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(c.Tag) {
+		case "caption":
+			if err := me.element(c); err != nil {
+				return err
+			}
+		case "tr":
+			if err := addRow(c); err != nil {
+				return err
+			}
+		case "thead", "tbody", "tfoot":
+			for _, row := range Flatten(c.Children) {
+				if tr, ok := row.(*Element); ok && strings.EqualFold(tr.Tag, "tr") {
+					if err := addRow(tr); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	format := func(row []string) string {
+		var sb strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				sb.WriteString("  ")
+			}
+			sb.WriteString(cell)
+			sb.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		return strings.TrimRight(sb.String(), " ")
+	}
+
+	me.block(2)
+	for i, row := range rows {
+		if i > 0 {
+			me.block(1)
+		}
+		me.lines(format(row))
+		if i == 0 && header {
+			underline := make([]string, len(row))
+			for j := range row {
+				underline[j] = strings.Repeat("-", widths[j])
+			}
+			me.block(1)
+			me.lines(format(underline))
+		}
+	}
+	me.block(2)
+	return nil
+}
+
+// attr returns the rendered value of the attribute key of e, or "" when it
+// is missing or boolean.
+func (me *textWriter) attr(e *Element, key string) (string, error) {
+	switch v := e.Attrs[key].(type) {
+	case nil, bool:
+		return "", nil
+	default:
+		return me.r.attrString(e.Tag, key, v)
+	}
+}
+
+// stripTags returns the text of an HTML fragment.
+func stripTags(s string) string {
+	var sb strings.Builder
+	inTag := false
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+		case !inTag:
+			sb.WriteRune(r)
+		}
+	}
+	return html.UnescapeString(sb.String())
+}
@@ -1,6 +1,7 @@
 package g
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -80,3 +81,72 @@ func TestText_Render_Error(t *testing.T) {
 		t.Errorf("Text.Render() should not return error, got: %v", err)
 	}
 }
+
+func TestRenderText(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     Node
+		expected string
+	}{
+		{
+			name:     "Blocks and inline elements",
+			node:     Div(H1(Text("Title")), P(Text("Some "), Strong(Text("bold")), Text("\n  text.")), Div(Text("a")), Div(Text("b"))),
+			expected: "Title\n=====\n\nSome bold text.\n\na\nb\n",
+		},
+		{
+			name:     "Line breaks and rules",
+			node:     Empty(P(Text("a"), Br(), Text("b"), Br(), Br(), Text("c")), Hr(), H2(Text("Sub"))),
+			expected: "a\nb\n\nc\n\n----\n\nSub\n---\n",
+		},
+		{
+			name:     "Links and images",
+			node:     P(A(KV{"href": "/docs"}, Text("Docs")), Text(", "), A(KV{"href": "https://example.com"}, Text("https://example.com")), Text(", "), A(KV{"href": "mailto:a@example.com"}, Text("a@example.com")), Text(" "), Img(KV{"src": "/x.png", "alt": "logo"})),
+			expected: "Docs (/docs), https://example.com, a@example.com logo\n",
+		},
+		{
+			name:     "Unsafe link",
+			node:     A(KV{"href": "javascript:alert(1)"}, Text("x")),
+			expected: "x (about:invalid#g-unsafe-url)\n",
+		},
+		{
+			name:     "Lists",
+			node:     Empty(P(Text("Steps:")), Ol(KV{"start": "3"}, Li(Text("one")), Li(Text("two"), Ul(Li(Text("a")), Li(Text("b"))))), P(Text("End"))),
+			expected: "Steps:\n\n3. one\n4. two\n   - a\n   - b\n\nEnd\n",
+		},
+		{
+			name: "Table",
+			node: Table(
+				Thead(Tr(Th(Text("Item")), Th(Text("Qty")))),
+				Tbody(Tr(Td(Text("Apple")), Td(Text("3"))), Tr(Td(Text("Kiwi")), Td(Text("12")))),
+			),
+			expected: "Item   Qty\n-----  ---\nApple  3\nKiwi   12\n",
+		},
+		{
+			name:     "Blockquote and pre",
+			node:     Empty(Blockquote(P(Text("a")), P(Text("b"))), Pre(Text("x  y\n  z"))),
+			expected: "> a\n>\n> b\n\nx  y\n  z\n",
+		},
+		{
+			name:     "Code text",
+			node:     Pre(Code(CodeText("if a < b {\n\treturn\n}"))),
+			expected: "if a < b {\n\treturn\n}\n",
+		},
+		{
+			name:     "Skipped elements",
+			node:     Html(Head(Title(Text("T")), Style(Text("p {}"))), Body(P(Text("Hi")), Script(Text("x()")), Template(P(Text("t"))))),
+			expected: "Hi\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := RenderText(&sb, tt.node); err != nil {
+				t.Fatalf("RenderText() error: %v", err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("RenderText() = %q, want %q", sb.String(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Package turbo provides Hotwire Turbo (https://turbo.hotwired.dev) frames
+// and streams.
+package turbo
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/assaidy/g"
+)
+
+// MediaType is the content type of Turbo Stream responses.
+const MediaType = "text/vnd.turbo-stream.html"
+
+// Frame creates a <turbo-frame> that is navigated independently from the
+// rest of the page.
+//
+// https://turbo.hotwired.dev/reference/frames
+func Frame(id string, args ...any) *g.Element {
+	return g.CustomElement("turbo-frame", append([]any{g.KV{"id": id}}, args...)...)
+}
+
+// Action is the operation a stream performs on its target.
+type Action string
+
+const (
+	ActionAppend  Action = "append"
+	ActionPrepend Action = "prepend"
+	ActionReplace Action = "replace"
+	ActionUpdate  Action = "update"
+	ActionRemove  Action = "remove"
+	ActionBefore  Action = "before"
+	ActionAfter   Action = "after"
+)
+
+// Stream creates a <turbo-stream> applying action to the element with the
+// id target. The content is wrapped in a Template element; pass nil for
+// actions without content, such as ActionRemove.
+//
+// https://turbo.hotwired.dev/reference/streams
+func Stream(action Action, target string, content g.Node) *g.Element {
+	stream := g.CustomElement("turbo-stream", g.KV{"action": string(action), "target": target})
+	if content != nil {
+		stream.Children = append(stream.Children, g.Template(content))
+	}
+	return stream
+}
+
+// Append adds content at the end of the target's children.
+func Append(target string, content g.Node) *g.Element {
+	return Stream(ActionAppend, target, content)
+}
+
+// Prepend adds content at the beginning of the target's children.
+func Prepend(target string, content g.Node) *g.Element {
+	return Stream(ActionPrepend, target, content)
+}
+
+// Replace replaces the target with content.
+func Replace(target string, content g.Node) *g.Element {
+	return Stream(ActionReplace, target, content)
+}
+
+// Update replaces the children of the target with content.
+func Update(target string, content g.Node) *g.Element {
+	return Stream(ActionUpdate, target, content)
+}
+
+// Remove removes the target.
+func Remove(target string) *g.Element {
+	return Stream(ActionRemove, target, nil)
+}
+
+// Before inserts content right before the target.
+func Before(target string, content g.Node) *g.Element {
+	return Stream(ActionBefore, target, content)
+}
+
+// After inserts content right after the target.
+func After(target string, content g.Node) *g.Element {
+	return Stream(ActionAfter, target, content)
+}
+
+// IsStreamRequest reports whether r accepts Turbo Stream responses, as form
+// submissions made by Turbo do.
+func IsStreamRequest(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), MediaType)
+}
+
+// WriteStreams writes a Turbo Stream response made of the given streams.
+//
+// Example:
+//
+//	err := turbo.WriteStreams(w,
+//		turbo.Append("messages", messageItem(msg)),
+//		turbo.Update("count", g.Text(strconv.Itoa(count))),
+//	)
+func WriteStreams(w http.ResponseWriter, streams ...g.Node) error {
+	w.Header().Set("Content-Type", MediaType+"; charset=utf-8")
+	return g.Render(w, &g.Element{Children: streams})
+}
@@ -0,0 +1,87 @@
+package turbo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/assaidy/g"
+)
+
+func TestStreams(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     g.Node
+		expected string
+	}{
+		{
+			name:     "Frame",
+			node:     Frame("messages", g.KV{"src": "/messages"}, g.P(g.Text("Loading..."))),
+			expected: `<turbo-frame id="messages" src="/messages"><p>Loading...</p></turbo-frame>`,
+		},
+		{
+			name:     "Append",
+			node:     Append("messages", g.Li(g.Text("hi"))),
+			expected: `<turbo-stream action="append" target="messages"><template><li>hi</li></template></turbo-stream>`,
+		},
+		{
+			name:     "Replace",
+			node:     Replace("message_1", g.Li(g.Text("edited"))),
+			expected: `<turbo-stream action="replace" target="message_1"><template><li>edited</li></template></turbo-stream>`,
+		},
+		{
+			name:     "Remove",
+			node:     Remove("message_1"),
+			expected: `<turbo-stream action="remove" target="message_1"></turbo-stream>`,
+		},
+		{
+			name:     "After",
+			node:     Stream(ActionAfter, "x", g.Hr()),
+			expected: `<turbo-stream action="after" target="x"><template><hr></template></turbo-stream>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.node.Render()
+			if err != nil {
+				t.Errorf("Render() error: %v", err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("Render() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteStreams(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := WriteStreams(w,
+		Prepend("list", g.Li(g.Text("first"))),
+		Update("count", g.Text("2")),
+	)
+	if err != nil {
+		t.Fatalf("WriteStreams() error: %v", err)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/vnd.turbo-stream.html; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want %q", ct, "text/vnd.turbo-stream.html; charset=utf-8")
+	}
+	expected := `<turbo-stream action="prepend" target="list"><template><li>first</li></template></turbo-stream>` +
+		`<turbo-stream action="update" target="count"><template>2</template></turbo-stream>`
+	if w.Body.String() != expected {
+		t.Errorf("WriteStreams() = %q, want %q", w.Body.String(), expected)
+	}
+}
+
+func TestIsStreamRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/messages", nil)
+	if IsStreamRequest(r) {
+		t.Error("IsStreamRequest() = true for a request without Accept header")
+	}
+	r.Header.Set("Accept", "text/vnd.turbo-stream.html, text/html, application/xhtml+xml")
+	if !IsStreamRequest(r) {
+		t.Error("IsStreamRequest() = false for a Turbo form submission")
+	}
+}
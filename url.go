@@ -0,0 +1,142 @@
+package g
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnsafeURLPlaceholder replaces unsafe URLs under the URLReplace policy. It
+// is a valid URL that doesn't load or run anything.
+const UnsafeURLPlaceholder = "about:invalid#g-unsafe-url"
+
+// URLPolicy decides what happens to unsafe URLs found in URL attributes
+// (href, src, action, formaction, poster, cite and the entries of srcset).
+//
+// Relative URLs and the http, https, mailto and tel schemes are safe; any
+// other scheme, such as javascript: or data:, is unsafe.
+type URLPolicy int
+
+const (
+	// URLReplace renders unsafe URLs as UnsafeURLPlaceholder. It is the
+	// default.
+	URLReplace URLPolicy = iota
+	// URLError makes rendering fail on unsafe URLs.
+	URLError
+)
+
+// WithURLPolicy sets the policy applied to unsafe URLs.
+//
+// Example:
+//
+//	err := Render(w, page, WithURLPolicy(URLError))
+func WithURLPolicy(policy URLPolicy) RenderOption {
+	return func(r *renderer) {
+		r.urlPolicy = policy
+	}
+}
+
+// TrustedURL is a URL attribute value that is rendered as is, without being
+// checked. Use it only for URLs that don't come from users, e.g., a data:
+// URL built by the application.
+//
+// Example:
+//
+//	Img(KV{"src": TrustedURL("data:image/png;base64,iVBORw0KGgo=")})
+type TrustedURL string
+
+// urlAttrs are the attributes holding a URL. srcset holds a list of them.
+var urlAttrs = map[string]bool{
+	"action":     true,
+	"cite":       true,
+	"formaction": true,
+	"href":       true,
+	"poster":     true,
+	"src":        true,
+	"srcset":     true,
+}
+
+var safeSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+	"tel":    true,
+}
+
+// checkURL applies the URL policy to the value of a URL attribute. Other
+// attributes are returned unchanged.
+func (me *renderer) checkURL(tag, key, value string) (string, error) {
+	key = strings.ToLower(key)
+	if !urlAttrs[key] {
+		return value, nil
+	}
+
+	if key != "srcset" {
+		if isSafeURL(value) {
+			return value, nil
+		}
+		return me.unsafeURL(tag, key, value)
+	}
+
+	// srcset: comma separated "url [descriptor]" entries
+	entries := strings.Split(value, ",")
+	for i, entry := range entries {
+		trimmed := strings.TrimSpace(entry)
+		u, descriptor, _ := strings.Cut(trimmed, " ")
+		if isSafeURL(u) {
+			continue
+		}
+		replacement, err := me.unsafeURL(tag, key, u)
+		if err != nil {
+			return "", err
+		}
+		entries[i] = strings.TrimSpace(replacement + " " + descriptor)
+	}
+	return strings.Join(entries, ","), nil
+}
+
+func (me *renderer) unsafeURL(tag, key, value string) (string, error) {
+	if me.urlPolicy == URLError {
+		return "", fmt.Errorf("unsafe URL %q in attribute '%s' of <%s>", value, key, tag)
+	}
+	return UnsafeURLPlaceholder, nil
+}
+
+// isSafeURL reports whether s is a relative URL or uses a safe scheme.
+func isSafeURL(s string) bool {
+	scheme := URLScheme(s)
+	return scheme == "" || safeSchemes[scheme]
+}
+
+// URLScheme returns the scheme of the URL s as browsers read it, in lower
+// case, or "" for a relative URL. Browsers ignore surrounding spaces and
+// control characters, and tabs and newlines anywhere, so the scheme of
+// "java\tscript:" is "javascript".
+//
+// Example:
+//
+//	URLScheme(" JavaScript:alert(1)") // "javascript"
+//	URLScheme("/50%off.pdf")          // ""
+//
+// https://url.spec.whatwg.org/#scheme-state
+func URLScheme(s string) string {
+	s = strings.TrimFunc(s, func(r rune) bool { return r <= ' ' })
+	s = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, s)
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case i > 0 && ('0' <= c && c <= '9' || c == '+' || c == '-' || c == '.'):
+		case i > 0 && c == ':':
+			return strings.ToLower(s[:i])
+		default:
+			return ""
+		}
+	}
+	return ""
+}
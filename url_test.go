@@ -0,0 +1,156 @@
+package g
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestElement_Render_URLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		element  *Element
+		expected string
+	}{
+		{
+			name:     "Safe absolute URL",
+			element:  A(KV{"href": "https://example.com/?a=1&b=2"}),
+			expected: `<a href="https://example.com/?a=1&amp;b=2"></a>`,
+		},
+		{
+			name:     "Relative URLs",
+			element:  Empty(A(KV{"href": "/about"}), A(KV{"href": "#top"}), A(KV{"href": "page?x=a:b"})),
+			expected: `<a href="/about"></a><a href="#top"></a><a href="page?x=a:b"></a>`,
+		},
+		{
+			name:     "URLs net/url can't parse",
+			element:  Empty(A(KV{"href": "/files/50%off.pdf"}), A(KV{"href": "https://example.com/50%off"}), A(KV{"href": "javascript:x('50%off')"})),
+			expected: `<a href="/files/50%off.pdf"></a><a href="https://example.com/50%off"></a><a href="about:invalid#g-unsafe-url"></a>`,
+		},
+		{
+			name:     "mailto and tel",
+			element:  Empty(A(KV{"href": "mailto:a@b.c"}), A(KV{"href": "tel:+123"})),
+			expected: `<a href="mailto:a@b.c"></a><a href="tel:+123"></a>`,
+		},
+		{
+			name:     "javascript: URL",
+			element:  A(KV{"href": "javascript:alert(1)"}),
+			expected: `<a href="about:invalid#g-unsafe-url"></a>`,
+		},
+		{
+			name:     "Obfuscated javascript: URL",
+			element:  Empty(A(KV{"href": " JaVaScRiPt:alert(1)"}), A(KV{"href": "java\tscript:alert(1)"})),
+			expected: `<a href="about:invalid#g-unsafe-url"></a><a href="about:invalid#g-unsafe-url"></a>`,
+		},
+		{
+			name:     "data: URL",
+			element:  Img(KV{"src": "data:text/html;base64,PHNjcmlwdD4="}),
+			expected: `<img src="about:invalid#g-unsafe-url">`,
+		},
+		{
+			name: "Other URL attributes",
+			element: Empty(
+				Form(KV{"action": "javascript:x()"}),
+				Button(KV{"formaction": "javascript:x()"}),
+				Video(KV{"poster": "javascript:x()"}),
+				Blockquote(KV{"cite": "javascript:x()"}),
+			),
+			expected: `<form action="about:invalid#g-unsafe-url"></form><button formaction="about:invalid#g-unsafe-url"></button>` +
+				`<video poster="about:invalid#g-unsafe-url"></video><blockquote cite="about:invalid#g-unsafe-url"></blockquote>`,
+		},
+		{
+			name:     "srcset entries",
+			element:  Img(KV{"srcset": "a.png 1x, javascript:x() 2x, /c.png 3x"}),
+			expected: `<img srcset="a.png 1x,about:invalid#g-unsafe-url 2x, /c.png 3x">`,
+		},
+		{
+			name:     "Trusted URL",
+			element:  Img(KV{"src": TrustedURL("data:image/png;base64,iVBORw0KGgo=")}),
+			expected: `<img src="data:image/png;base64,iVBORw0KGgo=">`,
+		},
+		{
+			name:     "Non-URL attribute",
+			element:  Div(KV{"title": "javascript:alert(1)"}),
+			expected: `<div title="javascript:alert(1)"></div>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.element.Render()
+			if err != nil {
+				t.Errorf("Element.Render() error: %v", err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("Element.Render() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestURLScheme(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "https://example.com", expected: "https"},
+		{input: " JavaScript:alert(1)", expected: "javascript"},
+		{input: "java\tscript:alert(1)", expected: "javascript"},
+		{input: "/files/50%off.pdf", expected: ""},
+		{input: "page?a=b:c", expected: ""},
+		{input: "1x:y", expected: ""},
+		{input: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		if result := URLScheme(tt.input); result != tt.expected {
+			t.Errorf("URLScheme(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestRender_URLError(t *testing.T) {
+	tests := []struct {
+		name    string
+		node    Node
+		wantErr bool
+	}{
+		{
+			name:    "Unsafe URL",
+			node:    Div(P(A(KV{"href": "javascript:alert(1)"}))),
+			wantErr: true,
+		},
+		{
+			name:    "Unsafe srcset entry",
+			node:    Img(KV{"srcset": "a.png 1x, data:x 2x"}),
+			wantErr: true,
+		},
+		{
+			name:    "Trusted URL",
+			node:    A(KV{"href": TrustedURL("javascript:void(0)")}),
+			wantErr: false,
+		},
+		{
+			name:    "Safe URL",
+			node:    A(KV{"href": "/home"}),
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Render(&buf, tt.node, WithURLPolicy(URLError))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiff_UnsafeURL(t *testing.T) {
+	patches := Diff(A(KV{"href": "/a"}), A(KV{"href": "javascript:alert(1)"}))
+	if len(patches) != 1 || patches[0].Value != UnsafeURLPlaceholder {
+		t.Errorf("Diff() = %+v, want the href set to %q", patches, UnsafeURLPlaceholder)
+	}
+}
@@ -0,0 +1,568 @@
+package g
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Severity tells how serious an Issue is.
+type Severity int
+
+const (
+	// SeverityWarning marks markup that is valid but likely a mistake.
+	SeverityWarning Severity = iota
+	// SeverityError marks invalid markup. Browsers fix it up while parsing,
+	// so the DOM doesn't match the tree.
+	SeverityError
+)
+
+func (me Severity) String() string {
+	switch me {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(me))
+	}
+}
+
+// Issue is a problem found by Validate.
+//
+// Path is the list of child indexes of the offending node, counted like the
+// Path of a Patch.
+type Issue struct {
+	Path     []int
+	Severity Severity
+	Message  string
+}
+
+func (me Issue) String() string {
+	return fmt.Sprintf("%s at %v: %s", me.Severity, me.Path, me.Message)
+}
+
+// ValidationError is returned by Render in strict mode when the tree has
+// SeverityError issues.
+type ValidationError struct {
+	Issues []Issue // the SeverityError issues
+}
+
+func (me *ValidationError) Error() string {
+	messages := make([]string, len(me.Issues))
+	for i, issue := range me.Issues {
+		messages[i] = issue.String()
+	}
+	return "invalid HTML: " + strings.Join(messages, "; ")
+}
+
+// WithStrict makes Render validate the tree first and fail with a
+// *ValidationError when Validate reports errors. Warnings are ignored.
+//
+// Example:
+//
+//	err := Render(w, page, WithStrict())
+func WithStrict() RenderOption {
+	return func(r *renderer) {
+		r.strict = true
+	}
+}
+
+// Validate checks node against the content models of the HTML standard and
+// returns the issues found, in tree order.
+//
+// It reports elements placed where they aren't allowed (e.g., a Li outside
+// a list, a Div inside a P, a Tr directly under a Table), text where only
+// elements are allowed, interactive content nested inside A or Button,
+// children of void elements and ids used more than once. The root elements
+// aren't checked against a parent, so components can be validated on their
+// own.
+//
+// Custom elements, Svg and Math content aren't checked, and unknown tags get
+// a warning.
+//
+// Example:
+//
+//	issues := Validate(P(Div(Text("x"))))
+//	// [error at [0 0]: <div> is not allowed in <p>, which accepts phrasing content]
+//
+// https://html.spec.whatwg.org/multipage/dom.html#content-models
+func Validate(node Node) []Issue {
+	v := &validator{}
+	for i, child := range Flatten([]Node{node}) {
+		if e, ok := child.(*Element); ok {
+			v.element([]int{i}, e, anyContent, nil)
+		}
+	}
+	issues := append(v.issues, DuplicateIDs(node)...)
+	slices.SortStableFunc(issues, func(a, b Issue) int {
+		return slices.Compare(a.Path, b.Path)
+	})
+	return issues
+}
+
+// DuplicateIDs returns a SeverityError issue for every element of node
+// whose id, as rendered, is the id of an element before it.
+//
+// Example:
+//
+//	issues := DuplicateIDs(Div(P(KV{"id": "a"}), P(KV{"id": "a"})))
+//	// [error at [0 1]: id "a" is used more than once]
+func DuplicateIDs(node Node) []Issue {
+	r := newRenderer(InDocument(node))
+	seen := map[string]bool{}
+	var issues []Issue
+	var visit func(path []int, nodes []Node)
+	visit = func(path []int, nodes []Node) {
+		for i, child := range Flatten(nodes) {
+			e, ok := child.(*Element)
+			if !ok {
+				continue
+			}
+			path := childPath(path, i)
+			if id, ok := r.renderedID(e); ok {
+				switch value, isID := e.Attrs["id"].(*ID); {
+				case !seen[id]:
+					seen[id] = true
+				case isID:
+					issues = append(issues, Issue{Path: path, Severity: SeverityError, Message: fmt.Sprintf("%s is the id of more than one element", value)})
+				default:
+					issues = append(issues, Issue{Path: path, Severity: SeverityError, Message: fmt.Sprintf("id %q is used more than once", id)})
+				}
+			}
+			visit(path, e.Children)
+		}
+	}
+	visit(nil, []Node{node})
+	return issues
+}
+
+// renderedID returns the id of e as rendered, without surrounding spaces,
+// and whether e has one.
+func (me *renderer) renderedID(e *Element) (string, bool) {
+	value, ok := e.Attrs["id"]
+	if _, isBool := value.(bool); !ok || value == nil || isBool {
+		return "", false
+	}
+	id, err := me.attrString(e.Tag, "id", value)
+	return strings.TrimSpace(id), err == nil
+}
+
+// strictErr returns a *ValidationError holding the errors found in node, or
+// nil if there are none.
+func strictErr(node Node) error {
+	var errs []Issue
+	for _, issue := range Validate(node) {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Issues: errs}
+}
+
+// category is a set of content categories.
+type category uint
+
+const (
+	catMetadata category = 1 << iota
+	catFlow
+	catPhrasing
+	catInteractive
+	catHeading
+	catScriptSupporting
+)
+
+const (
+	flow     = catFlow
+	phrasing = catFlow | catPhrasing
+)
+
+// content is the content model of an element: what its children may be.
+type content struct {
+	desc  string              // e.g., "phrasing content"
+	text  bool                // whether non-whitespace text is allowed
+	allow func(*Element) bool // nil means anything is allowed
+
+	// transparent marks elements whose content model is the one of their
+	// parent.
+	transparent bool
+}
+
+var (
+	anyContent      = content{text: true}
+	flowContent     = content{desc: "flow content", text: true, allow: inCategory(catFlow)}
+	phrasingContent = content{desc: "phrasing content", text: true, allow: inCategory(catPhrasing)}
+	metadataContent = content{desc: "metadata content", allow: inCategory(catMetadata)}
+	textContent     = content{desc: "text", text: true, allow: func(*Element) bool { return false }}
+	noContent       = content{desc: "nothing", allow: func(*Element) bool { return false }}
+)
+
+func inCategory(c category) func(*Element) bool {
+	return func(e *Element) bool {
+		return categoriesOf(e)&c != 0
+	}
+}
+
+// tagsContent returns a content model accepting the given tags and, like most
+// models in the standard, script and template.
+func tagsContent(desc string, tags ...string) content {
+	return content{desc: desc, allow: func(e *Element) bool {
+		return slices.Contains(tags, strings.ToLower(e.Tag)) || categoriesOf(e)&catScriptSupporting != 0
+	}}
+}
+
+// orTags returns c also accepting the given tags.
+func orTags(c content, tags ...string) content {
+	desc := c.desc
+	for _, tag := range tags {
+		desc += fmt.Sprintf(", <%s>", tag)
+	}
+	return content{desc: desc, text: c.text, allow: func(e *Element) bool {
+		return c.allow == nil || c.allow(e) || slices.Contains(tags, strings.ToLower(e.Tag))
+	}}
+}
+
+var transparent = content{transparent: true}
+
+// elementSpec describes an element of the standard.
+type elementSpec struct {
+	categories category
+	content    content
+}
+
+// elementSpecs is set in init, since the content models refer back to it
+// through categoriesOf.
+var elementSpecs map[string]elementSpec
+
+func init() {
+	elementSpecs = map[string]elementSpec{
+		// document and metadata
+		"html":  {0, content{desc: "a <head> followed by a <body>", allow: isTag("head", "body")}},
+		"head":  {0, metadataContent},
+		"body":  {0, flowContent},
+		"base":  {catMetadata, noContent},
+		"link":  {catMetadata, noContent},
+		"meta":  {catMetadata, noContent},
+		"style": {catMetadata, textContent},
+		"title": {catMetadata, textContent},
+
+		// scripting
+		"script":   {catMetadata | phrasing | catScriptSupporting, textContent},
+		"template": {catMetadata | phrasing | catScriptSupporting, anyContent},
+		"noscript": {catMetadata | phrasing, transparent},
+		"slot":     {phrasing, transparent},
+		"canvas":   {phrasing, transparent},
+
+		// sections and grouping
+		"address":    {flow, flowContent},
+		"article":    {flow, flowContent},
+		"aside":      {flow, flowContent},
+		"blockquote": {flow, flowContent},
+		"dialog":     {flow, flowContent},
+		"div":        {flow, flowContent},
+		"figure":     {flow, orTags(flowContent, "figcaption")},
+		"figcaption": {0, flowContent},
+		"footer":     {flow, flowContent},
+		"header":     {flow, flowContent},
+		"hgroup":     {flow | catHeading, tagsContent("<p> and headings", "p", "h1", "h2", "h3", "h4", "h5", "h6")},
+		"h1":         {flow | catHeading, phrasingContent},
+		"h2":         {flow | catHeading, phrasingContent},
+		"h3":         {flow | catHeading, phrasingContent},
+		"h4":         {flow | catHeading, phrasingContent},
+		"h5":         {flow | catHeading, phrasingContent},
+		"h6":         {flow | catHeading, phrasingContent},
+		"hr":         {flow, noContent},
+		"main":       {flow, flowContent},
+		"nav":        {flow, flowContent},
+		"p":          {flow, phrasingContent},
+		"pre":        {flow, phrasingContent},
+		"search":     {flow, flowContent},
+		"section":    {flow, flowContent},
+
+		// lists
+		"ul":   {flow, tagsContent("<li>", "li")},
+		"ol":   {flow, tagsContent("<li>", "li")},
+		"menu": {flow, tagsContent("<li>", "li")},
+		"li":   {0, flowContent},
+		"dl":   {flow, tagsContent("<dt>, <dd> and <div>", "dt", "dd", "div")},
+		"dt":   {0, flowContent},
+		"dd":   {0, flowContent},
+
+		// text-level semantics
+		"a":      {phrasing, transparent},
+		"abbr":   {phrasing, phrasingContent},
+		"b":      {phrasing, phrasingContent},
+		"bdi":    {phrasing, phrasingContent},
+		"bdo":    {phrasing, phrasingContent},
+		"br":     {phrasing, noContent},
+		"cite":   {phrasing, phrasingContent},
+		"code":   {phrasing, phrasingContent},
+		"data":   {phrasing, phrasingContent},
+		"del":    {phrasing, transparent},
+		"dfn":    {phrasing, phrasingContent},
+		"em":     {phrasing, phrasingContent},
+		"i":      {phrasing, phrasingContent},
+		"ins":    {phrasing, transparent},
+		"kbd":    {phrasing, phrasingContent},
+		"mark":   {phrasing, phrasingContent},
+		"q":      {phrasing, phrasingContent},
+		"rp":     {0, textContent},
+		"rt":     {0, phrasingContent},
+		"ruby":   {phrasing, orTags(phrasingContent, "rt", "rp")},
+		"s":      {phrasing, phrasingContent},
+		"samp":   {phrasing, phrasingContent},
+		"small":  {phrasing, phrasingContent},
+		"span":   {phrasing, phrasingContent},
+		"strong": {phrasing, phrasingContent},
+		"sub":    {phrasing, phrasingContent},
+		"sup":    {phrasing, phrasingContent},
+		"time":   {phrasing, phrasingContent},
+		"u":      {phrasing, phrasingContent},
+		"var":    {phrasing, phrasingContent},
+		"wbr":    {phrasing, noContent},
+
+		// embedded content
+		"area":        {phrasing, noContent},
+		"audio":       {phrasing, transparent},
+		"embed":       {phrasing | catInteractive, noContent},
+		"fencedframe": {phrasing | catInteractive, noContent},
+		"iframe":      {phrasing | catInteractive, noContent},
+		"img":         {phrasing, noContent},
+		"map":         {phrasing, transparent},
+		"math":        {phrasing, anyContent},
+		"object":      {phrasing, transparent},
+		"picture":     {phrasing, tagsContent("<source> and <img>", "source", "img")},
+		"source":      {0, noContent},
+		"svg":         {phrasing, anyContent},
+		"track":       {0, noContent},
+		"video":       {phrasing, transparent},
+
+		// tables
+		"table":    {flow, tagsContent("<caption>, <colgroup>, <thead>, <tbody> and <tfoot>", "caption", "colgroup", "thead", "tbody", "tfoot")},
+		"caption":  {0, flowContent},
+		"colgroup": {0, tagsContent("<col>", "col")},
+		"col":      {0, noContent},
+		"thead":    {0, tagsContent("<tr>", "tr")},
+		"tbody":    {0, tagsContent("<tr>", "tr")},
+		"tfoot":    {0, tagsContent("<tr>", "tr")},
+		"tr":       {0, tagsContent("<td> and <th>", "td", "th")},
+		"td":       {0, flowContent},
+		"th":       {0, flowContent},
+
+		// forms
+		"button":          {phrasing | catInteractive, phrasingContent},
+		"datalist":        {phrasing, orTags(phrasingContent, "option")},
+		"fieldset":        {flow, orTags(flowContent, "legend")},
+		"form":            {flow, flowContent},
+		"input":           {phrasing, noContent},
+		"label":           {phrasing | catInteractive, phrasingContent},
+		"legend":          {0, orTags(phrasingContent, "h1", "h2", "h3", "h4", "h5", "h6")},
+		"meter":           {phrasing, phrasingContent},
+		"optgroup":        {0, tagsContent("<option> and <legend>", "option", "legend")},
+		"option":          {0, phrasingContent},
+		"output":          {phrasing, phrasingContent},
+		"progress":        {phrasing, phrasingContent},
+		"select":          {phrasing | catInteractive, tagsContent("<option>, <optgroup>, <hr>, <div> and <button>", "option", "optgroup", "hr", "div", "button", "noscript")},
+		"selectedcontent": {0, noContent},
+		"textarea":        {phrasing | catInteractive, textContent},
+
+		// interactive elements
+		"details": {flow | catInteractive, orTags(flowContent, "summary")},
+		"summary": {0, orTags(phrasingContent, "h1", "h2", "h3", "h4", "h5", "h6", "hgroup")},
+	}
+}
+
+// bodyOKRels are the link types that make a <link> allowed in the body.
+var bodyOKRels = []string{"dns-prefetch", "modulepreload", "pingback", "preconnect", "prefetch", "preload", "stylesheet"}
+
+// categoriesOf returns the content categories of e, including the ones that
+// depend on its attributes.
+func categoriesOf(e *Element) category {
+	tag := strings.ToLower(e.Tag)
+	spec, ok := elementSpecs[tag]
+	if !ok { // custom and unknown elements
+		return phrasing
+	}
+
+	c := spec.categories
+	switch tag {
+	case "a":
+		if hasAttr(e, "href") {
+			c |= catInteractive
+		}
+	case "audio", "video":
+		if hasAttr(e, "controls") {
+			c |= catInteractive
+		}
+	case "img":
+		if hasAttr(e, "usemap") {
+			c |= catInteractive
+		}
+	case "input":
+		if t, _ := e.Attrs["type"].(string); !strings.EqualFold(t, "hidden") {
+			c |= catInteractive
+		}
+	case "link":
+		rels, _ := e.Attrs["rel"].(string)
+		for rel := range strings.FieldsSeq(strings.ToLower(rels)) {
+			if slices.Contains(bodyOKRels, rel) {
+				c |= phrasing
+			}
+		}
+		if hasAttr(e, "itemprop") {
+			c |= phrasing
+		}
+	case "meta":
+		if hasAttr(e, "itemprop") {
+			c |= phrasing
+		}
+	}
+	return c
+}
+
+// hasAttr reports whether the attribute key is rendered.
+func hasAttr(e *Element, key string) bool {
+	v, ok := e.Attrs[key]
+	if b, isBool := v.(bool); isBool {
+		return b
+	}
+	return ok && v != nil
+}
+
+// forbidden is a kind of element that must not appear inside an ancestor.
+type forbidden struct {
+	ancestor string
+	desc     string
+	match    func(*Element) bool
+}
+
+// forbiddenDescendants lists, by tag, the elements that must not appear
+// anywhere inside an element.
+var forbiddenDescendants = map[string]forbidden{
+	"a":      {desc: "interactive content", match: inCategory(catInteractive)},
+	"button": {desc: "interactive content", match: inCategory(catInteractive)},
+	"label":  {desc: "<label>", match: isTag("label")},
+	"form":   {desc: "<form>", match: isTag("form")},
+	"header": {desc: "<header> and <footer>", match: isTag("header", "footer")},
+	"footer": {desc: "<header> and <footer>", match: isTag("header", "footer")},
+	"address": {desc: "<address>, <header>, <footer> and headings", match: func(e *Element) bool {
+		return isTag("address", "header", "footer")(e) || categoriesOf(e)&catHeading != 0
+	}},
+	"dfn":      {desc: "<dfn>", match: isTag("dfn")},
+	"meter":    {desc: "<meter>", match: isTag("meter")},
+	"progress": {desc: "<progress>", match: isTag("progress")},
+	"caption":  {desc: "<table>", match: isTag("table")},
+}
+
+func isTag(tags ...string) func(*Element) bool {
+	return func(e *Element) bool {
+		return slices.Contains(tags, strings.ToLower(e.Tag))
+	}
+}
+
+type validator struct {
+	issues []Issue
+}
+
+func (me *validator) add(path []int, severity Severity, format string, args ...any) {
+	me.issues = append(me.issues, Issue{Path: path, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// element checks e, found at path, and its descendants. inherited is the
+// content model of the parent, used by transparent elements, and forbids
+// are the restrictions set by the ancestors.
+func (me *validator) element(path []int, e *Element, inherited content, forbids []forbidden) {
+	tag := strings.ToLower(e.Tag)
+	for _, f := range forbids {
+		if f.match(e) {
+			me.add(path, SeverityError, "<%s> is not allowed inside <%s>, which can't contain %s", tag, f.ancestor, f.desc)
+			break
+		}
+	}
+
+	if voidTags[tag] {
+		if !e.IsVoid {
+			me.add(path, SeverityError, "<%s> is a void element, but IsVoid is false", tag)
+		} else if len(e.Children) > 0 {
+			me.add(path, SeverityError, "void element <%s> has children, which are never rendered", tag)
+		}
+		return
+	}
+
+	spec, known := elementSpecs[tag]
+	if !known {
+		if !strings.Contains(tag, "-") {
+			me.add(path, SeverityWarning, "unknown element <%s>", tag)
+		}
+		return
+	}
+	if tag == "svg" || tag == "math" {
+		return
+	}
+
+	model := spec.content
+	switch {
+	case tag == "template": // its content is a separate document fragment
+		forbids = nil
+	case tag == "audio" || tag == "video":
+		model = orTags(inherited, "source", "track")
+	case model.transparent:
+		model = inherited
+	}
+	if f, ok := forbiddenDescendants[tag]; ok {
+		f.ancestor = tag
+		forbids = append(slices.Clip(forbids), f)
+	}
+
+	children := Flatten(e.Children)
+	for i, child := range children {
+		childPath := childPath(path, i)
+		switch c := child.(type) {
+		case Text:
+			if !model.text && strings.TrimSpace(string(c)) != "" {
+				me.add(childPath, SeverityError, "text is not allowed in <%s>, which accepts %s", tag, model.desc)
+			}
+		case *Element:
+			if model.allow != nil && !model.allow(c) {
+				childTag := strings.ToLower(c.Tag)
+				if tag == "table" && childTag == "tr" {
+					me.add(childPath, SeverityError, "<tr> is not allowed directly in <table>; browsers wrap it in a <tbody>")
+				} else {
+					me.add(childPath, SeverityError, "<%s> is not allowed in <%s>, which accepts %s", childTag, tag, model.desc)
+				}
+			}
+			me.element(childPath, c, model, forbids)
+		}
+	}
+
+	me.order(path, tag, children)
+}
+
+// order checks the elements that must come first, or only once, in e.
+func (me *validator) order(path []int, tag string, children []Node) {
+	first := map[string]string{"html": "head", "details": "summary", "fieldset": "legend"}[tag]
+	seen := map[string]bool{}
+	elementIndex := 0
+	for i, child := range children {
+		c, ok := child.(*Element)
+		if !ok {
+			continue
+		}
+		childTag := strings.ToLower(c.Tag)
+		switch {
+		case tag == "html" && seen[childTag]:
+			me.add(childPath(path, i), SeverityError, "<html> has more than one <%s>", childTag)
+		case first != "" && childTag == first && elementIndex > 0:
+			me.add(childPath(path, i), SeverityError, "<%s> must be the first child of <%s>", childTag, tag)
+		}
+		seen[childTag] = true
+		elementIndex++
+	}
+
+	if tag == "head" && !seen["title"] {
+		me.add(path, SeverityWarning, "<head> has no <title>")
+	}
+}
@@ -0,0 +1,157 @@
+package g
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     Node
+		expected []string
+	}{
+		{
+			name: "Valid document",
+			node: Html(
+				Head(Title(Text("Home")), Meta(KV{"charset": "utf-8"})),
+				Body(
+					Header(Nav(Ul(Li(A(KV{"href": "/"}, Text("Home")))))),
+					Main(P(Text("Hello "), Strong(Text("world"))), Table(Tbody(Tr(Td(Div(Text("x"))))))),
+				),
+			),
+			expected: nil,
+		},
+		{
+			name:     "Li outside a list",
+			node:     Div(Li(Text("x"))),
+			expected: []string{"error at [0 0]: <li> is not allowed in <div>, which accepts flow content"},
+		},
+		{
+			name:     "Div inside P",
+			node:     P(Text("a"), Div(Text("b"))),
+			expected: []string{"error at [0 1]: <div> is not allowed in <p>, which accepts phrasing content"},
+		},
+		{
+			name:     "Tr directly under Table",
+			node:     Table(Tr(Td(Text("x")))),
+			expected: []string{"error at [0 0]: <tr> is not allowed directly in <table>; browsers wrap it in a <tbody>"},
+		},
+		{
+			name: "Interactive content inside Button",
+			node: Button(Span(A(KV{"href": "/"}, Text("x"))), Input(KV{"type": "hidden"})),
+			expected: []string{
+				"error at [0 0 0]: <a> is not allowed inside <button>, which can't contain interactive content",
+			},
+		},
+		{
+			name: "Children of a void element",
+			node: Div(&Element{Tag: "br", IsVoid: true, Children: []Node{Text("x")}}, &Element{Tag: "img"}),
+			expected: []string{
+				"error at [0 0]: void element <br> has children, which are never rendered",
+				"error at [0 1]: <img> is a void element, but IsVoid is false",
+			},
+		},
+		{
+			name:     "Transparent element takes the parent's content model",
+			node:     P(A(KV{"href": "/"}, Div())),
+			expected: []string{"error at [0 0 0]: <div> is not allowed in <a>, which accepts phrasing content"},
+		},
+		{
+			name:     "Text in a list",
+			node:     Ul(Text(" "), Li(), Text("x")),
+			expected: []string{"error at [0 2]: text is not allowed in <ul>, which accepts <li>"},
+		},
+		{
+			name: "Tagless elements are flattened",
+			node: Empty(Ul(Empty(Li(), Empty(Li())), Empty(P()))),
+			expected: []string{
+				"error at [0 2]: <p> is not allowed in <ul>, which accepts <li>",
+			},
+		},
+		{
+			name: "Document structure",
+			node: Html(Body(), Head(), Body()),
+			expected: []string{
+				"warning at [0 1]: <head> has no <title>",
+				"error at [0 1]: <head> must be the first child of <html>",
+				"error at [0 2]: <html> has more than one <body>",
+			},
+		},
+		{
+			name:     "Root elements aren't checked against a parent",
+			node:     Li(Text("x")),
+			expected: nil,
+		},
+		{
+			name: "Custom, unknown and foreign elements",
+			node: P(CustomElement("my-card", Div()), CustomElement("foo"), Svg(Div())),
+			expected: []string{
+				"warning at [0 1]: unknown element <foo>",
+			},
+		},
+		{
+			name: "Conditional categories",
+			node: Head(Title(), Link(KV{"rel": "stylesheet", "href": "/a.css"}), P(Link(KV{"rel": "stylesheet"}), Link(KV{"rel": "icon"}))),
+			expected: []string{
+				"error at [0 2]: <p> is not allowed in <head>, which accepts metadata content",
+				"error at [0 2 1]: <link> is not allowed in <p>, which accepts phrasing content",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result []string
+			for _, issue := range Validate(tt.node) {
+				result = append(result, issue.String())
+			}
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("Validate() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDuplicateIDs(t *testing.T) {
+	id := UniqueID("x")
+	node := Div(
+		P(KV{"id": "a"}),
+		Empty(P(KV{"id": attrValue{value: " a "}})),
+		P(KV{"id": id}, Span(KV{"id": id})),
+		P(KV{"id": true}, Span(KV{"id": true})),
+	)
+	var result []string
+	for _, issue := range DuplicateIDs(node) {
+		result = append(result, issue.String())
+	}
+	want := []string{
+		`error at [0 1]: id "a" is used more than once`,
+		`error at [0 2 0]: UniqueID("x") is the id of more than one element`,
+	}
+	if !slices.Equal(result, want) {
+		t.Errorf("DuplicateIDs() = %q, want %q", result, want)
+	}
+}
+
+func TestRender_Strict(t *testing.T) {
+	var buf bytes.Buffer
+	err := Render(&buf, Div(P(Div())), WithStrict())
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Issues) != 1 {
+		t.Fatalf("Render() error = %v, want a *ValidationError with 1 issue", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Render() wrote %q, want nothing", buf.String())
+	}
+
+	// warnings don't fail the render
+	if err := Render(&buf, Head(), WithStrict()); err != nil {
+		t.Errorf("Render() error = %v, want nil", err)
+	}
+	if err := Render(&buf, Div(P(Div()))); err != nil {
+		t.Errorf("Render() without WithStrict() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,78 @@
+package g
+
+import "strings"
+
+// Walk calls fn for node and every node below it, in document order: the
+// children of elements, tagless ones included, and the nodes of HeadItems.
+// When fn returns false, the nodes below the node are skipped.
+//
+// Example:
+//
+//	var scripts int
+//	Walk(page, func(node Node) bool {
+//		if e, ok := node.(*Element); ok && e.Tag == "script" {
+//			scripts++
+//		}
+//		return true
+//	})
+func Walk(node Node, fn func(Node) bool) {
+	if item, ok := node.(*headItem); ok {
+		Walk(item.node, fn)
+		return
+	}
+	if node == nil || !fn(node) {
+		return
+	}
+	if e, ok := node.(*Element); ok {
+		for _, child := range e.Children {
+			Walk(child, fn)
+		}
+	}
+}
+
+// Flatten returns nodes with tagless elements replaced by their children
+// and adjacent Text nodes merged, so that the result maps one-to-one to the
+// DOM nodes of the rendered HTML. nil nodes are dropped.
+//
+// The Path of a Patch, and of the issues found by Validate and the other
+// checkers, counts children after flattening.
+//
+// Example:
+//
+//	Flatten([]Node{Text("a"), Empty(Text("b"), Br())})
+//	// [Text("ab") Br()]
+func Flatten(nodes []Node) []Node {
+	var result []Node
+	var text strings.Builder
+	flushText := func() {
+		if text.Len() > 0 {
+			result = append(result, Text(text.String()))
+			text.Reset()
+		}
+	}
+
+	var flatten func(nodes []Node)
+	flatten = func(nodes []Node) {
+		for _, node := range nodes {
+			switch n := node.(type) {
+			case nil:
+			case Text:
+				text.WriteString(string(n))
+			case *Element:
+				if n.Tag == "" {
+					flatten(n.Children)
+					continue
+				}
+				flushText()
+				result = append(result, n)
+			default:
+				flushText()
+				result = append(result, n)
+			}
+		}
+	}
+	flatten(nodes)
+	flushText()
+
+	return result
+}
@@ -0,0 +1,47 @@
+package g
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	page := Html(
+		Head(Title(Text("t"))),
+		Body(
+			HeadItem(Script(KV{"src": "/a.js"})),
+			Empty(P(Text("a")), Text("b")),
+			Template(Span()),
+		),
+	)
+
+	var visited []string
+	Walk(page, func(node Node) bool {
+		switch n := node.(type) {
+		case *Element:
+			visited = append(visited, "<"+n.Tag+">")
+			return n.Tag != "template"
+		case Text:
+			visited = append(visited, string(n))
+		}
+		return true
+	})
+
+	want := "<html> <head> <title> t <body> <script> <> <p> a b <template>"
+	if got := strings.Join(visited, " "); got != want {
+		t.Errorf("Walk() visited %q, want %q", got, want)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	result := Flatten([]Node{Text("a"), nil, Empty(Text("b"), Br(), Empty(Text("c"))), Text("d")})
+	want := []Node{Text("ab"), Br(), Text("cd")}
+	if len(result) != len(want) {
+		t.Fatalf("Flatten() = %v, want %v", result, want)
+	}
+	for i := range want {
+		if !Equal(result[i], want[i]) {
+			t.Errorf("Flatten()[%d] = %v, want %v", i, result[i], want[i])
+		}
+	}
+}